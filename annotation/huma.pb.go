@@ -11,6 +11,7 @@ import (
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	reflect "reflect"
+	sync "sync"
 )
 
 const (
@@ -20,7 +21,316 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Casing describes how enum value labels are transformed before being used
+// as the JSON representation of the value.
+type Casing int32
+
+const (
+	// Use the protobuf label as-is, e.g. `STATUS_ACTIVE`.
+	Casing_CASING_DEFAULT Casing = 0
+	// Lowercase snake case, e.g. `status_active`.
+	Casing_CASING_LOWER Casing = 1
+	// Lowercase kebab case, e.g. `status-active`.
+	Casing_CASING_KEBAB Casing = 2
+	// Lower camel case, e.g. `statusActive`.
+	Casing_CASING_CAMEL Casing = 3
+)
+
+// Enum value maps for Casing.
+var (
+	Casing_name = map[int32]string{
+		0: "CASING_DEFAULT",
+		1: "CASING_LOWER",
+		2: "CASING_KEBAB",
+		3: "CASING_CAMEL",
+	}
+	Casing_value = map[string]int32{
+		"CASING_DEFAULT": 0,
+		"CASING_LOWER":   1,
+		"CASING_KEBAB":   2,
+		"CASING_CAMEL":   3,
+	}
+)
+
+func (x Casing) Enum() *Casing {
+	p := new(Casing)
+	*p = x
+	return p
+}
+
+func (x Casing) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Casing) Descriptor() protoreflect.EnumDescriptor {
+	return file_huma_proto_enumTypes[0].Descriptor()
+}
+
+func (Casing) Type() protoreflect.EnumType {
+	return &file_huma_proto_enumTypes[0]
+}
+
+func (x Casing) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Casing.Descriptor instead.
+func (Casing) EnumDescriptor() ([]byte, []int) {
+	return file_huma_proto_rawDescGZIP(), []int{0}
+}
+
+// OneofStyle describes how a one-of group is represented in JSON.
+type OneofStyle int32
+
+const (
+	// Members are sibling fields of the message. Only one may be set, which is
+	// enforced by the generated resolver.
+	OneofStyle_FLAT OneofStyle = 0
+	// Like `FLAT`, but the group is also documented as a JSON Schema `oneOf`
+	// so that generated SDKs can model the union.
+	OneofStyle_UNION OneofStyle = 1
+	// Members are nested in an object named after the group, e.g.
+	// `"only_one": {"tag": "..."}`, which groups mutually exclusive options in
+	// docs. Only one member may be set.
+	OneofStyle_WRAPPED OneofStyle = 2
+)
+
+// Enum value maps for OneofStyle.
+var (
+	OneofStyle_name = map[int32]string{
+		0: "FLAT",
+		1: "UNION",
+		2: "WRAPPED",
+	}
+	OneofStyle_value = map[string]int32{
+		"FLAT":    0,
+		"UNION":   1,
+		"WRAPPED": 2,
+	}
+)
+
+func (x OneofStyle) Enum() *OneofStyle {
+	p := new(OneofStyle)
+	*p = x
+	return p
+}
+
+func (x OneofStyle) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OneofStyle) Descriptor() protoreflect.EnumDescriptor {
+	return file_huma_proto_enumTypes[1].Descriptor()
+}
+
+func (OneofStyle) Type() protoreflect.EnumType {
+	return &file_huma_proto_enumTypes[1]
+}
+
+func (x OneofStyle) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OneofStyle.Descriptor instead.
+func (OneofStyle) EnumDescriptor() ([]byte, []int) {
+	return file_huma_proto_rawDescGZIP(), []int{1}
+}
+
+// Tag is an extra Go struct tag for a generated field, e.g. `yaml:"name"`.
+type Tag struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Tag) Reset() {
+	*x = Tag{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_huma_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tag) ProtoMessage() {}
+
+func (x *Tag) ProtoReflect() protoreflect.Message {
+	mi := &file_huma_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tag.ProtoReflect.Descriptor instead.
+func (*Tag) Descriptor() ([]byte, []int) {
+	return file_huma_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Tag) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Tag) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Extension is a JSON Schema extension for a generated field.
+type Extension struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Key must start with `x-`, e.g. `x-internal`.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Value is JSON encoded, e.g. `true` or `"some string"`.
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Extension) Reset() {
+	*x = Extension{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_huma_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Extension) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Extension) ProtoMessage() {}
+
+func (x *Extension) ProtoReflect() protoreflect.Message {
+	mi := &file_huma_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Extension.ProtoReflect.Descriptor instead.
+func (*Extension) Descriptor() ([]byte, []int) {
+	return file_huma_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Extension) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Extension) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 var file_huma_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84841,
+		Name:          "huma.strip_enum_prefix",
+		Tag:           "varint,84841,opt,name=strip_enum_prefix",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84842,
+		Name:          "huma.exclude_enum_zero",
+		Tag:           "varint,84842,opt,name=exclude_enum_zero",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84843,
+		Name:          "huma.numeric_enums",
+		Tag:           "varint,84843,opt,name=numeric_enums",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84844,
+		Name:          "huma.emit_zero_fields",
+		Tag:           "varint,84844,opt,name=emit_zero_fields",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84845,
+		Name:          "huma.go_package",
+		Tag:           "bytes,84845,opt,name=go_package",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84846,
+		Name:          "huma.json_alias_hook",
+		Tag:           "bytes,84846,opt,name=json_alias_hook",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*Casing)(nil),
+		Field:         84841,
+		Name:          "huma.casing",
+		Tag:           "varint,84841,opt,name=casing,enum=huma.Casing",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84842,
+		Name:          "huma.strip_prefix",
+		Tag:           "varint,84842,opt,name=strip_prefix",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84843,
+		Name:          "huma.exclude_zero",
+		Tag:           "varint,84843,opt,name=exclude_zero",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84844,
+		Name:          "huma.numeric",
+		Tag:           "varint,84844,opt,name=numeric",
+		Filename:      "huma.proto",
+	},
 	{
 		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
 		ExtensionType: (*bool)(nil),
@@ -29,6 +339,22 @@ var file_huma_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "varint,84841,opt,name=exclude",
 		Filename:      "huma.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84842,
+		Name:          "huma.label",
+		Tag:           "bytes,84842,opt,name=label",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84843,
+		Name:          "huma.canonical",
+		Tag:           "varint,84843,opt,name=canonical",
+		Filename:      "huma.proto",
+	},
 	{
 		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
 		ExtensionType: (*bool)(nil),
@@ -77,14 +403,207 @@ var file_huma_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,84846,opt,name=example",
 		Filename:      "huma.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84847,
+		Name:          "huma.flatten",
+		Tag:           "varint,84847,opt,name=flatten",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84848,
+		Name:          "huma.go_type",
+		Tag:           "bytes,84848,opt,name=go_type",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84849,
+		Name:          "huma.from_proto",
+		Tag:           "bytes,84849,opt,name=from_proto",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84850,
+		Name:          "huma.to_proto",
+		Tag:           "bytes,84850,opt,name=to_proto",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         84851,
+		Name:          "huma.json_alias",
+		Tag:           "bytes,84851,rep,name=json_alias",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: ([]string)(nil),
+		Field:         84852,
+		Name:          "huma.audience",
+		Tag:           "bytes,84852,rep,name=audience",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84853,
+		Name:          "huma.required_scope",
+		Tag:           "bytes,84853,opt,name=required_scope",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84854,
+		Name:          "huma.sensitive",
+		Tag:           "varint,84854,opt,name=sensitive",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: ([]*Tag)(nil),
+		Field:         84855,
+		Name:          "huma.tags",
+		Tag:           "bytes,84855,rep,name=tags",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: ([]*Extension)(nil),
+		Field:         84856,
+		Name:          "huma.extensions",
+		Tag:           "bytes,84856,rep,name=extensions",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         84857,
+		Name:          "huma.emit_zero",
+		Tag:           "varint,84857,opt,name=emit_zero",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84858,
+		Name:          "huma.validator",
+		Tag:           "bytes,84858,opt,name=validator",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.OneofOptions)(nil),
+		ExtensionType: (*OneofStyle)(nil),
+		Field:         84841,
+		Name:          "huma.oneof_style",
+		Tag:           "varint,84841,opt,name=oneof_style,enum=huma.OneofStyle",
+		Filename:      "huma.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.OneofOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         84842,
+		Name:          "huma.discriminator",
+		Tag:           "bytes,84842,opt,name=discriminator",
+		Filename:      "huma.proto",
+	},
 }
 
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// Strip enum prefix removes the enum name prefix from all enum values in
+	// the file, e.g. `FRUITS_APPLE` becomes `APPLE` in the `Fruits` enum.
+	//
+	// optional bool strip_enum_prefix = 84841;
+	E_StripEnumPrefix = &file_huma_proto_extTypes[0]
+	// Exclude enum zero removes the zero value, e.g. `FRUITS_UNSPECIFIED`, from
+	// all enums in the file. The zero value is then represented by an absent
+	// field.
+	//
+	// optional bool exclude_enum_zero = 84842;
+	E_ExcludeEnumZero = &file_huma_proto_extTypes[1]
+	// Numeric enums represents all enums in the file as their protobuf integer
+	// values rather than string labels.
+	//
+	// optional bool numeric_enums = 84843;
+	E_NumericEnums = &file_huma_proto_extTypes[2]
+	// Emit zero fields always serializes all scalar and enum fields in the file
+	// outside of one-of groups, even if they have a zero value like `false` or
+	// `0`. Fields can override this via `emit_zero`. Huma requires these fields
+	// on input unless the operation uses `schemaext.RequestSchema`.
+	//
+	// optional bool emit_zero_fields = 84844;
+	E_EmitZeroFields = &file_huma_proto_extTypes[3]
+	// Go package sets the import path and optional name of the generated Huma
+	// package, in the same format as `go_package`, e.g.
+	// `example.com/foo/barapi;barapi`. It defaults to the protobuf-go package
+	// with the package suffix added.
+	//
+	// optional string go_package = 84845;
+	E_GoPackage = &file_huma_proto_extTypes[4]
+	// JSON alias hook is a function called by generated resolvers for each
+	// deprecated `json_alias` used in a request, e.g. to log it or to set a
+	// response header the operation declares. Unqualified names refer to a
+	// function in the generated package. It must have the signature
+	// `func(ctx huma.Context, model, alias string)`.
+	//
+	// optional string json_alias_hook = 84846;
+	E_JsonAliasHook = &file_huma_proto_extTypes[5]
+)
+
+// Extension fields to descriptorpb.EnumOptions.
+var (
+	// Casing sets how all values of this enum are represented in JSON. Values
+	// with an explicit `label` are not modified.
+	//
+	// optional huma.Casing casing = 84841;
+	E_Casing = &file_huma_proto_extTypes[6]
+	// Strip prefix removes the enum name prefix from the values of this enum,
+	// e.g. `FRUITS_APPLE` becomes `APPLE` in the `Fruits` enum. Overrides the
+	// file-level `strip_enum_prefix` option.
+	//
+	// optional bool strip_prefix = 84842;
+	E_StripPrefix = &file_huma_proto_extTypes[7]
+	// Exclude zero removes the zero value, e.g. `FRUITS_UNSPECIFIED`, from this
+	// enum. The zero value is then represented by an absent field. Overrides
+	// the file-level `exclude_enum_zero` option.
+	//
+	// optional bool exclude_zero = 84843;
+	E_ExcludeZero = &file_huma_proto_extTypes[8]
+	// Numeric represents this enum as its protobuf integer values rather than
+	// string labels, e.g. for clients using the protojson integer form.
+	// Overrides the file-level `numeric_enums` option.
+	//
+	// optional bool numeric = 84844;
+	E_Numeric = &file_huma_proto_extTypes[9]
+)
+
 // Extension fields to descriptorpb.EnumValueOptions.
 var (
 	// Excludes this enum value from the available options.
 	//
 	// optional bool exclude = 84841;
-	E_Exclude = &file_huma_proto_extTypes[0]
+	E_Exclude = &file_huma_proto_extTypes[10]
+	// Label overrides the JSON representation of this enum value, which is
+	// otherwise the protobuf label, e.g. `STATUS_ACTIVE` might become `active`.
+	//
+	// optional string label = 84842;
+	E_Label = &file_huma_proto_extTypes[11]
+	// Canonical marks which value to use when converting from protobuf if the
+	// enum has `allow_alias` set and several values share the same number. By
+	// default the first declared value is used. All aliases are accepted as
+	// input.
+	//
+	// optional bool canonical = 84843;
+	E_Canonical = &file_huma_proto_extTypes[12]
 )
 
 // Extension fields to descriptorpb.FieldOptions.
@@ -92,36 +611,120 @@ var (
 	// Public marks that a field should be included in the generated Huma model.
 	//
 	// optional bool public = 84841;
-	E_Public = &file_huma_proto_extTypes[1]
+	E_Public = &file_huma_proto_extTypes[13]
 	// Read-only marks that a field is set by the server. The client can only
 	// read its value, e.g. resource creation date.
 	//
 	// optional bool read_only = 84842;
-	E_ReadOnly = &file_huma_proto_extTypes[2]
+	E_ReadOnly = &file_huma_proto_extTypes[14]
 	// Name specifies the Huma Go field name. For example, a field might be cased
 	// as `Mp2T` but should be `MP2T` because it is a non-common initialism.
 	// Setting this field also updates the JSON name unless it has also been
 	// overridden.
 	//
 	// optional string name = 84843;
-	E_Name = &file_huma_proto_extTypes[3]
+	E_Name = &file_huma_proto_extTypes[15]
 	// JSON specifies the Huma field's JSON name. Usually this is derived from
 	// the field's name, but this option allows you to override it. For example,
 	// a field named `MP2T` might become `mp2_t` but should be `mp2t`.
 	//
 	// optional string json = 84844;
-	E_Json = &file_huma_proto_extTypes[4]
+	E_Json = &file_huma_proto_extTypes[16]
 	// Multiple-of specifies that the number must be a multiple of this value
 	// or validation will fail.
 	//
 	// optional int32 multiple_of = 84845;
-	E_MultipleOf = &file_huma_proto_extTypes[5]
+	E_MultipleOf = &file_huma_proto_extTypes[17]
 	// Example provides a sample value for documentation purposes. This string
 	// value will get put directly into the Huma field example and will get
 	// written out in JSON Schema with the appropriate type.
 	//
 	// optional string example = 84846;
-	E_Example = &file_huma_proto_extTypes[6]
+	E_Example = &file_huma_proto_extTypes[18]
+	// Flatten hoists the public fields of a message field into the parent's
+	// Huma model by embedding it, e.g. to flatten a nested `Metadata` message.
+	// The nested message is still used when converting to and from protobuf.
+	//
+	// optional bool flatten = 84847;
+	E_Flatten = &file_huma_proto_extTypes[19]
+	// Go type overrides the Huma Go type of a primitive field, e.g.
+	// `github.com/shopspring/decimal.Decimal`. Without converter functions the
+	// value is converted using a Go type conversion.
+	//
+	// optional string go_type = 84848;
+	E_GoType = &file_huma_proto_extTypes[20]
+	// From proto is a function converting the protobuf value into the custom
+	// `go_type`, e.g. `github.com/shopspring/decimal.NewFromString`. It must
+	// have the signature `func(protoValue) (customValue, error)`. Unqualified
+	// names refer to a function in the generated Huma package.
+	//
+	// optional string from_proto = 84849;
+	E_FromProto = &file_huma_proto_extTypes[21]
+	// To proto is a function converting the custom `go_type` into the protobuf
+	// value. It must have the signature `func(customValue) (protoValue, error)`.
+	//
+	// optional string to_proto = 84850;
+	E_ToProto = &file_huma_proto_extTypes[22]
+	// JSON alias accepts input using an old JSON name for this field, e.g. after
+	// renaming it via `json`. Aliases are documented as deprecated and values
+	// are moved to the canonical field by the generated resolver.
+	//
+	// repeated string json_alias = 84851;
+	E_JsonAlias = &file_huma_proto_extTypes[23]
+	// Audience exposes a field to an audience like `partner` or `admin`. Models
+	// for each audience are only generated when selected via the `audience`
+	// plugin parameter, and use the audience as a type name suffix.
+	//
+	// repeated string audience = 84852;
+	E_Audience = &file_huma_proto_extTypes[24]
+	// Required scope hides a field from callers without the given scope, e.g.
+	// `billing:read`, when calling the generated `Redact` method.
+	//
+	// optional string required_scope = 84853;
+	E_RequiredScope = &file_huma_proto_extTypes[25]
+	// Sensitive masks a field when logging the generated model, e.g. for PII
+	// like email addresses.
+	//
+	// optional bool sensitive = 84854;
+	E_Sensitive = &file_huma_proto_extTypes[26]
+	// Tags adds raw extra struct tags to the generated field, e.g.
+	// `[(huma.tags) = {key: "yaml", value: "name"}]`.
+	//
+	// repeated huma.Tag tags = 84855;
+	E_Tags = &file_huma_proto_extTypes[27]
+	// Extensions adds JSON Schema `x-*` extensions to the generated field, e.g.
+	// `[(huma.extensions) = {key: "x-internal", value: "true"}]`.
+	//
+	// repeated huma.Extension extensions = 84856;
+	E_Extensions = &file_huma_proto_extTypes[28]
+	// Emit zero always serializes the scalar or enum field, even if it has a
+	// zero value like `false` or `0`. It is ignored on other fields and one-of
+	// members. Huma requires the field on input unless the operation uses
+	// `schemaext.RequestSchema`.
+	//
+	// optional bool emit_zero = 84857;
+	E_EmitZero = &file_huma_proto_extTypes[29]
+	// Validator is a function called by the generated resolver to validate the
+	// field, e.g. `ValidateCountry` in the generated package or a package
+	// qualified name like `example.com/pkg.ValidateCountry`. It must have the
+	// signature `func(value T) error` where `T` is the field's Go type.
+	//
+	// optional string validator = 84858;
+	E_Validator = &file_huma_proto_extTypes[30]
+)
+
+// Extension fields to descriptorpb.OneofOptions.
+var (
+	// One-of style sets how the group is represented in JSON.
+	//
+	// optional huma.OneofStyle oneof_style = 84841;
+	E_OneofStyle = &file_huma_proto_extTypes[31]
+	// Discriminator adds a property naming the set member of a `UNION` group,
+	// e.g. `kind`. It is set when converting from protobuf and validated on
+	// input.
+	//
+	// optional string discriminator = 84842;
+	E_Discriminator = &file_huma_proto_extTypes[32]
 )
 
 var File_huma_proto protoreflect.FileDescriptor
@@ -130,57 +733,235 @@ var file_huma_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x68, 0x75, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x68, 0x75,
 	0x6d, 0x61, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x40, 0x0a, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12,
-	0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0xe9, 0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78, 0x63, 0x6c,
-	0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x3a, 0x3a, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2d, 0x0a, 0x03, 0x54, 0x61, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x22, 0x33, 0x0a, 0x09, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2a, 0x52, 0x0a, 0x06, 0x43, 0x61, 0x73, 0x69,
+	0x6e, 0x67, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x41, 0x53, 0x49, 0x4e, 0x47, 0x5f, 0x44, 0x45, 0x46,
+	0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x41, 0x53, 0x49, 0x4e, 0x47,
+	0x5f, 0x4c, 0x4f, 0x57, 0x45, 0x52, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x41, 0x53, 0x49,
+	0x4e, 0x47, 0x5f, 0x4b, 0x45, 0x42, 0x41, 0x42, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x41,
+	0x53, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x41, 0x4d, 0x45, 0x4c, 0x10, 0x03, 0x2a, 0x2e, 0x0a, 0x0a,
+	0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x4c,
+	0x41, 0x54, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x4e, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x57, 0x52, 0x41, 0x50, 0x50, 0x45, 0x44, 0x10, 0x02, 0x3a, 0x4d, 0x0a, 0x11,
+	0x73, 0x74, 0x72, 0x69, 0x70, 0x5f, 0x65, 0x6e, 0x75, 0x6d, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xe9, 0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x73, 0x74, 0x72, 0x69, 0x70, 0x45, 0x6e,
+	0x75, 0x6d, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x88, 0x01, 0x01, 0x3a, 0x4d, 0x0a, 0x11, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x65, 0x6e, 0x75, 0x6d, 0x5f, 0x7a, 0x65, 0x72, 0x6f,
+	0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xea,
+	0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x45,
+	0x6e, 0x75, 0x6d, 0x5a, 0x65, 0x72, 0x6f, 0x88, 0x01, 0x01, 0x3a, 0x46, 0x0a, 0x0d, 0x6e, 0x75,
+	0x6d, 0x65, 0x72, 0x69, 0x63, 0x5f, 0x65, 0x6e, 0x75, 0x6d, 0x73, 0x12, 0x1c, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xeb, 0x96, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x75, 0x6d, 0x73, 0x88,
+	0x01, 0x01, 0x3a, 0x4b, 0x0a, 0x10, 0x65, 0x6d, 0x69, 0x74, 0x5f, 0x7a, 0x65, 0x72, 0x6f, 0x5f,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xec, 0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x65, 0x6d,
+	0x69, 0x74, 0x5a, 0x65, 0x72, 0x6f, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x88, 0x01, 0x01, 0x3a,
+	0x40, 0x0a, 0x0a, 0x67, 0x6f, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xed, 0x96, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x6f, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x88, 0x01,
+	0x01, 0x3a, 0x49, 0x0a, 0x0f, 0x6a, 0x73, 0x6f, 0x6e, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f,
+	0x68, 0x6f, 0x6f, 0x6b, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0xee, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6a, 0x73, 0x6f, 0x6e,
+	0x41, 0x6c, 0x69, 0x61, 0x73, 0x48, 0x6f, 0x6f, 0x6b, 0x88, 0x01, 0x01, 0x3a, 0x47, 0x0a, 0x06,
+	0x63, 0x61, 0x73, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xe9, 0x96, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x68,
+	0x75, 0x6d, 0x61, 0x2e, 0x43, 0x61, 0x73, 0x69, 0x6e, 0x67, 0x52, 0x06, 0x63, 0x61, 0x73, 0x69,
+	0x6e, 0x67, 0x88, 0x01, 0x01, 0x3a, 0x44, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x70, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0xea, 0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x74, 0x72,
+	0x69, 0x70, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x88, 0x01, 0x01, 0x3a, 0x44, 0x0a, 0x0c, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x7a, 0x65, 0x72, 0x6f, 0x12, 0x1c, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e,
+	0x75, 0x6d, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xeb, 0x96, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0b, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5a, 0x65, 0x72, 0x6f, 0x88, 0x01,
+	0x01, 0x3a, 0x3b, 0x0a, 0x07, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x12, 0x1c, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6e, 0x75, 0x6d, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xec, 0x96, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x88, 0x01, 0x01, 0x3a, 0x40,
+	0x0a, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xe9, 0x96, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01,
+	0x3a, 0x3c, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xea, 0x96, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x3a, 0x44,
+	0x0a, 0x09, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x12, 0x21, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e,
+	0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xeb,
+	0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61,
+	0x6c, 0x88, 0x01, 0x01, 0x3a, 0x3a, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xe9, 0x96,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x88, 0x01, 0x01,
+	0x3a, 0x3f, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x12, 0x1d, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xea, 0x96, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x88, 0x01,
+	0x01, 0x3a, 0x36, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xeb, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x3a, 0x36, 0x0a, 0x04, 0x6a, 0x73, 0x6f,
+	0x6e, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xec, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x3a, 0x43, 0x0a, 0x0b, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x5f, 0x6f, 0x66,
 	0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
 	0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0xe9, 0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x88,
-	0x01, 0x01, 0x3a, 0x3f, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x12,
+	0xed, 0x96, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c,
+	0x65, 0x4f, 0x66, 0x88, 0x01, 0x01, 0x3a, 0x3c, 0x0a, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xee, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x88, 0x01, 0x01, 0x3a, 0x3c, 0x0a, 0x07, 0x66, 0x6c, 0x61, 0x74, 0x74, 0x65, 0x6e, 0x12,
 	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xea,
-	0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79,
-	0x88, 0x01, 0x01, 0x3a, 0x36, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
-	0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xeb, 0x96, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x3a, 0x36, 0x0a, 0x04, 0x6a,
-	0x73, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0xec, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e,
-	0x88, 0x01, 0x01, 0x3a, 0x43, 0x0a, 0x0b, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x5f,
-	0x6f, 0x66, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x18, 0xed, 0x96, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69,
-	0x70, 0x6c, 0x65, 0x4f, 0x66, 0x88, 0x01, 0x01, 0x3a, 0x3c, 0x0a, 0x07, 0x65, 0x78, 0x61, 0x6d,
-	0x70, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xef,
+	0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x66, 0x6c, 0x61, 0x74, 0x74, 0x65, 0x6e, 0x88,
+	0x01, 0x01, 0x3a, 0x3b, 0x0a, 0x07, 0x67, 0x6f, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf0, 0x96, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x6f, 0x54, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x3a,
+	0x41, 0x0a, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1d, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf1, 0x96, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x88,
+	0x01, 0x01, 0x3a, 0x3d, 0x0a, 0x08, 0x74, 0x6f, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf2, 0x96,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6f, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x88, 0x01,
+	0x01, 0x3a, 0x3e, 0x0a, 0x0a, 0x6a, 0x73, 0x6f, 0x6e, 0x5f, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12,
+	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf3,
+	0x96, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6a, 0x73, 0x6f, 0x6e, 0x41, 0x6c, 0x69, 0x61,
+	0x73, 0x3a, 0x3b, 0x0a, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf4, 0x96, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x3a, 0x49,
+	0x0a, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x73, 0x63, 0x6f, 0x70, 0x65,
+	0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xf5, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65,
+	0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x88, 0x01, 0x01, 0x3a, 0x40, 0x0a, 0x09, 0x73, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf6, 0x96, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73,
+	0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x88, 0x01, 0x01, 0x3a, 0x3e, 0x0a, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0xee, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x78, 0x61, 0x6d,
-	0x70, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6c, 0x61, 0x62, 0x73,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x68, 0x75, 0x6d, 0x61,
-	0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x6e, 0x73, 0x18, 0xf7, 0x96, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x68, 0x75, 0x6d,
+	0x61, 0x2e, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x3a, 0x50, 0x0a, 0x0a, 0x65,
+	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf8, 0x96, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x68, 0x75, 0x6d, 0x61, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x3a, 0x3f, 0x0a,
+	0x09, 0x65, 0x6d, 0x69, 0x74, 0x5f, 0x7a, 0x65, 0x72, 0x6f, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf9, 0x96, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x65, 0x6d, 0x69, 0x74, 0x5a, 0x65, 0x72, 0x6f, 0x88, 0x01, 0x01, 0x3a, 0x40,
+	0x0a, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xfa, 0x96, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x3a, 0x55, 0x0a, 0x0b, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x5f, 0x73, 0x74, 0x79, 0x6c, 0x65, 0x12,
+	0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x4f, 0x6e, 0x65, 0x6f, 0x66, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xe9,
+	0x96, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x68, 0x75, 0x6d, 0x61, 0x2e, 0x4f, 0x6e,
+	0x65, 0x6f, 0x66, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x52, 0x0a, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x53,
+	0x74, 0x79, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x3a, 0x48, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72,
+	0x69, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4f, 0x6e, 0x65, 0x6f, 0x66,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xea, 0x96, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x64, 0x69, 0x73, 0x63, 0x72, 0x69, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x88, 0x01,
+	0x01, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x69, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x68, 0x75, 0x6d, 0x61, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_huma_proto_rawDescOnce sync.Once
+	file_huma_proto_rawDescData = file_huma_proto_rawDesc
+)
+
+func file_huma_proto_rawDescGZIP() []byte {
+	file_huma_proto_rawDescOnce.Do(func() {
+		file_huma_proto_rawDescData = protoimpl.X.CompressGZIP(file_huma_proto_rawDescData)
+	})
+	return file_huma_proto_rawDescData
 }
 
+var file_huma_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_huma_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_huma_proto_goTypes = []interface{}{
-	(*descriptorpb.EnumValueOptions)(nil), // 0: google.protobuf.EnumValueOptions
-	(*descriptorpb.FieldOptions)(nil),     // 1: google.protobuf.FieldOptions
+	(Casing)(0),                           // 0: huma.Casing
+	(OneofStyle)(0),                       // 1: huma.OneofStyle
+	(*Tag)(nil),                           // 2: huma.Tag
+	(*Extension)(nil),                     // 3: huma.Extension
+	(*descriptorpb.FileOptions)(nil),      // 4: google.protobuf.FileOptions
+	(*descriptorpb.EnumOptions)(nil),      // 5: google.protobuf.EnumOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 6: google.protobuf.EnumValueOptions
+	(*descriptorpb.FieldOptions)(nil),     // 7: google.protobuf.FieldOptions
+	(*descriptorpb.OneofOptions)(nil),     // 8: google.protobuf.OneofOptions
 }
 var file_huma_proto_depIdxs = []int32{
-	0, // 0: huma.exclude:extendee -> google.protobuf.EnumValueOptions
-	1, // 1: huma.public:extendee -> google.protobuf.FieldOptions
-	1, // 2: huma.read_only:extendee -> google.protobuf.FieldOptions
-	1, // 3: huma.name:extendee -> google.protobuf.FieldOptions
-	1, // 4: huma.json:extendee -> google.protobuf.FieldOptions
-	1, // 5: huma.multiple_of:extendee -> google.protobuf.FieldOptions
-	1, // 6: huma.example:extendee -> google.protobuf.FieldOptions
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	0, // [0:7] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	4,  // 0: huma.strip_enum_prefix:extendee -> google.protobuf.FileOptions
+	4,  // 1: huma.exclude_enum_zero:extendee -> google.protobuf.FileOptions
+	4,  // 2: huma.numeric_enums:extendee -> google.protobuf.FileOptions
+	4,  // 3: huma.emit_zero_fields:extendee -> google.protobuf.FileOptions
+	4,  // 4: huma.go_package:extendee -> google.protobuf.FileOptions
+	4,  // 5: huma.json_alias_hook:extendee -> google.protobuf.FileOptions
+	5,  // 6: huma.casing:extendee -> google.protobuf.EnumOptions
+	5,  // 7: huma.strip_prefix:extendee -> google.protobuf.EnumOptions
+	5,  // 8: huma.exclude_zero:extendee -> google.protobuf.EnumOptions
+	5,  // 9: huma.numeric:extendee -> google.protobuf.EnumOptions
+	6,  // 10: huma.exclude:extendee -> google.protobuf.EnumValueOptions
+	6,  // 11: huma.label:extendee -> google.protobuf.EnumValueOptions
+	6,  // 12: huma.canonical:extendee -> google.protobuf.EnumValueOptions
+	7,  // 13: huma.public:extendee -> google.protobuf.FieldOptions
+	7,  // 14: huma.read_only:extendee -> google.protobuf.FieldOptions
+	7,  // 15: huma.name:extendee -> google.protobuf.FieldOptions
+	7,  // 16: huma.json:extendee -> google.protobuf.FieldOptions
+	7,  // 17: huma.multiple_of:extendee -> google.protobuf.FieldOptions
+	7,  // 18: huma.example:extendee -> google.protobuf.FieldOptions
+	7,  // 19: huma.flatten:extendee -> google.protobuf.FieldOptions
+	7,  // 20: huma.go_type:extendee -> google.protobuf.FieldOptions
+	7,  // 21: huma.from_proto:extendee -> google.protobuf.FieldOptions
+	7,  // 22: huma.to_proto:extendee -> google.protobuf.FieldOptions
+	7,  // 23: huma.json_alias:extendee -> google.protobuf.FieldOptions
+	7,  // 24: huma.audience:extendee -> google.protobuf.FieldOptions
+	7,  // 25: huma.required_scope:extendee -> google.protobuf.FieldOptions
+	7,  // 26: huma.sensitive:extendee -> google.protobuf.FieldOptions
+	7,  // 27: huma.tags:extendee -> google.protobuf.FieldOptions
+	7,  // 28: huma.extensions:extendee -> google.protobuf.FieldOptions
+	7,  // 29: huma.emit_zero:extendee -> google.protobuf.FieldOptions
+	7,  // 30: huma.validator:extendee -> google.protobuf.FieldOptions
+	8,  // 31: huma.oneof_style:extendee -> google.protobuf.OneofOptions
+	8,  // 32: huma.discriminator:extendee -> google.protobuf.OneofOptions
+	0,  // 33: huma.casing:type_name -> huma.Casing
+	2,  // 34: huma.tags:type_name -> huma.Tag
+	3,  // 35: huma.extensions:type_name -> huma.Extension
+	1,  // 36: huma.oneof_style:type_name -> huma.OneofStyle
+	37, // [37:37] is the sub-list for method output_type
+	37, // [37:37] is the sub-list for method input_type
+	33, // [33:37] is the sub-list for extension type_name
+	0,  // [0:33] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_huma_proto_init() }
@@ -188,18 +969,46 @@ func file_huma_proto_init() {
 	if File_huma_proto != nil {
 		return
 	}
+	if !protoimpl.UnsafeEnabled {
+		file_huma_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Tag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_huma_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Extension); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_huma_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   0,
-			NumExtensions: 7,
+			NumEnums:      2,
+			NumMessages:   2,
+			NumExtensions: 33,
 			NumServices:   0,
 		},
 		GoTypes:           file_huma_proto_goTypes,
 		DependencyIndexes: file_huma_proto_depIdxs,
+		EnumInfos:         file_huma_proto_enumTypes,
+		MessageInfos:      file_huma_proto_msgTypes,
 		ExtensionInfos:    file_huma_proto_extTypes,
 	}.Build()
 	File_huma_proto = out.File
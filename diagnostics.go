@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Diagnostics collects problems found while generating code. Errors are
+// reported back to protoc, which fails without writing any files. Warnings are
+// printed to stderr while generation carries on with a fallback, e.g. ignoring
+// an invalid annotation.
+type Diagnostics struct {
+	// Errors are fatal problems, prefixed with their source location.
+	Errors []string
+
+	// Warnings are non-fatal problems, prefixed with their source location.
+	Warnings []string
+}
+
+// diagnostics for the current request, which is reset on every run.
+var diagnostics = &Diagnostics{}
+
+// location returns a `file.proto:line:col` location for a source code info
+// path, e.g. [4, 0, 2, 1] for the second field of the first message. If the
+// path has no location then the closest enclosing element is used, falling
+// back to just the file name.
+func location(file *descriptorpb.FileDescriptorProto, path []int32) string {
+	best := -1
+	var span []int32
+	for _, loc := range file.GetSourceCodeInfo().GetLocation() {
+		if len(loc.Path) > len(path) || len(loc.Path) <= best || len(loc.Span) < 2 {
+			continue
+		}
+
+		match := true
+		for i := range loc.Path {
+			if loc.Path[i] != path[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			best = len(loc.Path)
+			span = loc.Span
+		}
+	}
+
+	if span == nil {
+		return file.GetName()
+	}
+
+	// Spans are zero-based, while editors and compilers count from one.
+	return fmt.Sprintf("%s:%d:%d", file.GetName(), span[0]+1, span[1]+1)
+}
+
+// contains returns whether a problem was already reported. Files are
+// processed once per audience, which would otherwise repeat their problems.
+func contains(reported []string, msg string) bool {
+	for _, r := range reported {
+		if r == msg {
+			return true
+		}
+	}
+	return false
+}
+
+// warnf reports a non-fatal problem at a source code info path.
+func warnf(file *descriptorpb.FileDescriptorProto, path []int32, format string, args ...interface{}) {
+	msg := location(file, path) + ": warning: " + fmt.Sprintf(format, args...)
+	if contains(diagnostics.Warnings, msg) {
+		return
+	}
+	diagnostics.Warnings = append(diagnostics.Warnings, msg)
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+// errorf reports a fatal problem at a source code info path. Generation
+// continues so that all errors can be reported at once.
+func errorf(file *descriptorpb.FileDescriptorProto, path []int32, format string, args ...interface{}) {
+	msg := location(file, path) + ": " + fmt.Sprintf(format, args...)
+	if !contains(diagnostics.Errors, msg) {
+		diagnostics.Errors = append(diagnostics.Errors, msg)
+	}
+}
@@ -0,0 +1,1120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flosch/pongo2"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Packages used by the generated code. Identifiers from these are passed to
+// `emitter.P` so that protogen adds the imports when they are used.
+const (
+	fmtPackage         = protogen.GoImportPath("fmt")
+	httpPackage        = protogen.GoImportPath("net/http")
+	humaPackage        = protogen.GoImportPath("github.com/danielgtaylor/huma")
+	reflectPackage     = protogen.GoImportPath("reflect")
+	slogPackage        = protogen.GoImportPath("log/slog")
+	strconvPackage     = protogen.GoImportPath("strconv")
+	stringsPackage     = protogen.GoImportPath("strings")
+	timestamppbPackage = protogen.GoImportPath("google.golang.org/protobuf/types/known/timestamppb")
+)
+
+// qualifiedRegex matches package-qualified identifiers in model types, e.g.
+// `package2huma.Message` in `[]*package2huma.Message`.
+var qualifiedRegex = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// declRegex matches the start of a generated declaration for a model, e.g.
+// `type Message struct` or `func (m *Message) ToProto(...)`.
+var declRegex = regexp.MustCompile(`^\s*(?:type (\w+) |func \(m \*?(\w+)\))`)
+
+// emitter writes the generated Go code for a `File`. Package names used in
+// model types like `Field.GoType` are mapped back to their import paths so
+// that protogen can manage the imports, see `typ`.
+type emitter struct {
+	g    *protogen.GeneratedFile
+	file *File
+
+	// packages maps package names used in model types to import paths.
+	packages map[string]string
+
+	// src is a copy of the emitted source, used to locate parse errors.
+	src bytes.Buffer
+}
+
+// newEmitter returns an emitter writing to a new generated file in the Huma
+// package of the file.
+func newEmitter(plugin *protogen.Plugin, tFile *File, filename string) *emitter {
+	e := &emitter{
+		g:        plugin.NewGeneratedFile(filename, protogen.GoImportPath(tFile.GoPackage.ImportPath)),
+		file:     tFile,
+		packages: map[string]string{},
+	}
+
+	importPaths := make([]string, 0, len(tFile.Imports))
+	for importPath := range tFile.Imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	for _, importPath := range importPaths {
+		name := tFile.Imports[importPath]
+		if other, ok := e.packages[name]; ok {
+			errorf(tFile.Proto, nil, "Packages %s and %s have the same name %s, use the go_package parameter to rename one", other, importPath, name)
+			continue
+		}
+		e.packages[name] = importPath
+	}
+
+	return e
+}
+
+// P prints a line of generated code. Go identifiers are qualified and their
+// packages imported as needed.
+func (e *emitter) P(v ...interface{}) {
+	var line strings.Builder
+	for _, x := range v {
+		if ident, ok := x.(protogen.GoIdent); ok {
+			line.WriteString(e.g.QualifiedGoIdent(ident))
+		} else {
+			fmt.Fprint(&line, x)
+		}
+	}
+
+	e.g.P(line.String())
+	e.src.WriteString(line.String())
+	e.src.WriteByte('\n')
+}
+
+// typ returns a model type, e.g. `[]*package2huma.Message`, with its package
+// qualifiers replaced by the names protogen imports the packages as.
+func (e *emitter) typ(t string) string {
+	return qualifiedRegex.ReplaceAllStringFunc(t, func(s string) string {
+		dot := strings.Index(s, ".")
+		importPath, ok := e.packages[s[:dot]]
+		if !ok {
+			errorf(e.file.Proto, nil, "Unknown package %s in type %s", s[:dot], t)
+			return s
+		}
+		return e.g.QualifiedGoIdent(protogen.GoIdent{
+			GoName:       s[dot+1:],
+			GoImportPath: protogen.GoImportPath(importPath),
+		})
+	})
+}
+
+// proto returns the identifier of a protobuf-generated type of the file.
+func (e *emitter) proto(name string) protogen.GoIdent {
+	return protogen.GoImportPath(e.file.ProtoGoImport).Ident(name)
+}
+
+// check reports an error if the emitted code does not parse, pointing to the
+// model whose code is broken if possible. Broken files are skipped so they are
+// never written.
+func (e *emitter) check() {
+	src := e.src.Bytes()
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments); err != nil {
+		e.g.Skip()
+
+		if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+			if msg := declAt(e.file, src, list[0].Pos.Line); msg != nil {
+				errorf(e.file.Proto, msg.Path, "Generated code for %s does not parse: %v", msg.Name, err)
+				return
+			}
+		}
+		errorf(e.file.Proto, nil, "Generated code does not parse: %v", err)
+	}
+}
+
+// declAt returns the message whose generated declaration contains a line of
+// the source, if any.
+func declAt(tFile *File, src []byte, line int) *Message {
+	lines := strings.Split(string(src), "\n")
+	if line > len(lines) {
+		line = len(lines)
+	}
+
+	for i := line - 1; i >= 0; i-- {
+		if m := declRegex.FindStringSubmatch(lines[i]); m != nil {
+			name := m[1] + m[2]
+			for j := range tFile.Messages {
+				if tFile.Messages[j].Name == name {
+					return &tFile.Messages[j]
+				}
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// header writes the comment identifying the generated file.
+func (e *emitter) header() {
+	e.P("// Generated by the protocol buffer compiler.  DO NOT EDIT!")
+	e.P("// sources: ", e.file.Proto.GetName())
+	e.P("// plugin: protoc-gen-huma")
+}
+
+// formatFloat formats a validation bound for a struct tag, e.g. `0` or `0.5`.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// custom renders the user template for an extension point, if any, see the
+// `template` parameter. Templates get the file, the current message and field
+// if any, a `type` function which resolves package names in model types to
+// those used by the generated file's imports, and an `ident` function which
+// imports a package and returns a qualified identifier from it.
+func (e *emitter) custom(point string, msg *Message, f *Field) string {
+	tpl := e.file.Options.Templates[point]
+	if tpl == nil {
+		return ""
+	}
+
+	out, err := tpl.Execute(pongo2.Context{
+		"file":  e.file,
+		"msg":   msg,
+		"field": f,
+		"type":  e.typ,
+		"ident": func(importPath, name string) string {
+			return e.g.QualifiedGoIdent(protogen.GoImportPath(importPath).Ident(name))
+		},
+	})
+	if err != nil {
+		var path []int32
+		if f != nil {
+			path = f.Path
+		} else if msg != nil {
+			path = msg.Path
+		}
+		errorf(e.file.Proto, path, "Cannot render %s template: %v", point, err)
+		return ""
+	}
+
+	return strings.TrimSpace(out)
+}
+
+// tags returns the struct tags for a field, including backticks. Custom tags
+// from a user template are added last.
+func tags(f *Field, custom string) string {
+	v := f.Validation
+	tags := `json:"` + f.JSONName
+	if !v.IsRequired && !f.EmitZero {
+		tags += ",omitempty"
+	}
+	tags += `"`
+
+	if f.Enum != nil || f.IsDiscriminator {
+		tags += ` enum:"` + strings.Join(v.EnumValues, ",") + `"`
+	}
+	if v.HasMinimum {
+		tags += ` minimum:"` + formatFloat(v.Minimum) + `"`
+	}
+	if v.HasExclusiveMinimum {
+		tags += ` exclusiveMinimum:"` + formatFloat(v.ExclusiveMinimum) + `"`
+	}
+	if v.HasMaximum {
+		tags += ` maximum:"` + formatFloat(v.Maximum) + `"`
+	}
+	if v.HasExclusiveMaximum {
+		tags += ` exclusiveMaximum:"` + formatFloat(v.ExclusiveMaximum) + `"`
+	}
+	if v.MinLength != 0 {
+		tags += fmt.Sprintf(` minLength:"%d"`, v.MinLength)
+	}
+	if v.MaxLength != 0 {
+		tags += fmt.Sprintf(` maxLength:"%d"`, v.MaxLength)
+	}
+	if v.Pattern != "" {
+		tags += ` pattern:"` + v.Pattern + `"`
+	}
+	if v.Format != "" {
+		tags += ` format:"` + v.Format + `"`
+	}
+	if v.MinItems != 0 {
+		tags += fmt.Sprintf(` minItems:"%d"`, v.MinItems)
+	}
+	if v.MaxItems != 0 {
+		tags += fmt.Sprintf(` maxItems:"%d"`, v.MaxItems)
+	}
+	if v.Unique {
+		tags += ` uniqueItems:"true"`
+	}
+	if v.ReadOnly {
+		tags += ` readOnly:"true"`
+	}
+	if v.Deprecated {
+		tags += ` deprecated:"true"`
+	}
+	if v.MultipleOf != 0 {
+		tags += fmt.Sprintf(` multipleOf:"%d"`, v.MultipleOf)
+	}
+	if f.Example != "" {
+		tags += ` example:"` + f.Example + `"`
+	}
+	if f.Comment != "" {
+		tags += ` doc:"` + f.Comment + `"`
+	}
+
+	tags += f.ExtraTags
+	if custom != "" {
+		tags += " " + custom
+	}
+
+	return "`" + tags + "`"
+}
+
+// emitHuma writes the Huma models of a file along with their validation,
+// redaction, logging and protobuf conversion methods.
+func emitHuma(plugin *protogen.Plugin, tFile *File, filename string) {
+	e := newEmitter(plugin, tFile, filename)
+	e.header()
+	e.P("package ", tFile.GoPackage.Name)
+	e.P()
+
+	if header := e.custom("header", nil, nil); header != "" {
+		e.P(header)
+		e.P()
+	}
+
+	if len(tFile.Messages) > 0 {
+		// Every model has a resolver using Huma, so claim its package name before
+		// any other package named `huma` can take it.
+		e.g.QualifiedGoIdent(humaPackage.Ident("Context"))
+	}
+
+	for i := range tFile.Enums {
+		e.enum(&tFile.Enums[i])
+	}
+
+	for i := range tFile.Messages {
+		e.message(&tFile.Messages[i])
+	}
+
+	e.check()
+}
+
+// enumValue returns the Go value of an enum value.
+func enumValue(enum *Enum, value EnumValue) string {
+	if enum.IsNumeric {
+		return strconv.Itoa(int(value.Value))
+	}
+	return strconv.Quote(value.Label)
+}
+
+// enum writes an enum type with its values and the maps used to convert it
+// to and from protobuf.
+func (e *emitter) enum(enum *Enum) {
+	typ := "string"
+	if enum.IsNumeric {
+		typ = "int32"
+	}
+
+	if enum.Comment != "" {
+		e.P("// ", enum.Comment)
+	}
+	e.P("type ", enum.Name, " ", typ)
+	e.P()
+
+	e.P("const (")
+	for _, value := range enum.Values {
+		if value.Comment != "" {
+			e.P("// ", value.Comment)
+		}
+		e.P(enum.Name, value.Name, " ", enum.Name, " = ", enumValue(enum, value))
+	}
+	e.P(")")
+	e.P()
+
+	protoEnum := e.proto(enum.ProtoGoName)
+	e.P("var ", enum.Name, "ValuesMap map[", enum.Name, "]", protoEnum, " = map[", enum.Name, "]", protoEnum, "{")
+	for _, value := range enum.Values {
+		if !enum.IsNumeric || !value.IsAlias {
+			e.P(enumValue(enum, value), ": ", protoEnum, "(", value.Value, "),")
+		}
+	}
+	e.P("}")
+	e.P()
+
+	e.P("var ", enum.Name, "NamesMap map[", protoEnum, "]", enum.Name, " = map[", protoEnum, "]", enum.Name, "{")
+	for _, value := range enum.Values {
+		if !value.IsAlias {
+			e.P(value.Value, ": ", enum.Name, "(", enumValue(enum, value), "),")
+		}
+	}
+	e.P("}")
+	e.P()
+}
+
+// message writes a model and its methods.
+func (e *emitter) message(msg *Message) {
+	for _, f := range msg.Fields {
+		if f.IsEmbedded {
+			e.P("// ", f.Name, " flattens ", e.typ(f.GoType), " into ", msg.Name, ", see `resolveGenerated`.")
+			e.P("type ", f.Name, " = ", e.typ(f.GoType))
+			e.P()
+		}
+	}
+
+	if msg.Comment != "" {
+		e.P("// ", msg.Comment)
+	}
+	e.P("type ", msg.Name, " struct {")
+	for _, f := range msg.Fields {
+		if f.Comment != "" {
+			e.P("// ", f.Comment)
+		}
+		if f.IsEmbedded {
+			e.P(f.Name)
+		} else {
+			e.P(f.Name, " ", e.typ(f.GoType), " ", tags(f, e.custom("field_tags", msg, f)))
+		}
+		for _, alias := range f.Aliases {
+			e.P("// ", alias.Comment)
+			e.P(alias.Name, " ", e.typ(alias.GoType), " ", tags(alias, e.custom("field_tags", msg, alias)))
+		}
+	}
+	e.P("}")
+	e.P()
+
+	if len(msg.Extensions) > 0 {
+		e.schemaExtensions(msg)
+	}
+
+	if len(msg.OptionalInputs) > 0 {
+		names := make([]string, len(msg.OptionalInputs))
+		for i, name := range msg.OptionalInputs {
+			names[i] = strconv.Quote(name)
+		}
+
+		e.P("// OptionalInputFields returns fields which are always serialized but are")
+		e.P("// optional on input. See github.com/istreamlabs/protoc-gen-huma/schemaext for")
+		e.P("// usage.")
+		e.P("func (m *", msg.Name, ") OptionalInputFields() []string {")
+		e.P("return []string{", strings.Join(names, ", "), "}")
+		e.P("}")
+		e.P()
+	}
+
+	if msg.HasAliases {
+		e.resolveJSONAliases(msg)
+	}
+
+	for _, f := range msg.Fields {
+		if f.Validator != "" {
+			e.P("// ", f.Validator, " must be implemented to validate '", f.JSONName, "'")
+			e.P("// in '", msg.Name, "', see the `validator` annotation.")
+			e.P("var _ func(", e.typ(f.GoType), ") error = ", e.typ(f.Validator))
+			e.P()
+		}
+	}
+
+	e.resolve(msg)
+	e.redact(msg)
+	e.stringers(msg)
+	e.fromProto(msg)
+	e.toProto(msg)
+
+	if extra := e.custom("message", msg, nil); extra != "" {
+		e.P(extra)
+		e.P()
+	}
+}
+
+// schemaExtensions writes the `SchemaExtensions` method of a model.
+func (e *emitter) schemaExtensions(msg *Message) {
+	paths := make([]string, 0, len(msg.Extensions))
+	for path := range msg.Extensions {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	e.P("// SchemaExtensions returns JSON Schema extensions for the OpenAPI document.")
+	e.P("// See github.com/istreamlabs/protoc-gen-huma/schemaext for usage.")
+	e.P("func (m *", msg.Name, ") SchemaExtensions() map[string]map[string]interface{} {")
+	e.P("return map[string]map[string]interface{}{")
+	for _, path := range paths {
+		exts := msg.Extensions[path]
+		names := make([]string, 0, len(exts))
+		for name := range exts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		e.P(strconv.Quote(path), ": {")
+		for _, name := range names {
+			e.P(strconv.Quote(name), ": ", exts[name], ",")
+		}
+		e.P("},")
+	}
+	e.P("}")
+	e.P("}")
+	e.P()
+}
+
+// resolveJSONAliases writes the `ResolveJSONAliases` method of a model.
+func (e *emitter) resolveJSONAliases(msg *Message) {
+	e.P("// ResolveJSONAliases moves values set via deprecated JSON field aliases to")
+	e.P("// their canonical fields and returns the aliases which were used. Canonical")
+	e.P("// fields take precedence if both are set.")
+	e.P("func (m *", msg.Name, ") ResolveJSONAliases() []string {")
+	e.P("used := []string{}")
+	for _, f := range msg.Fields {
+		for _, alias := range f.Aliases {
+			e.P("if !", reflectPackage.Ident("ValueOf"), "(m.", alias.Name, ").IsZero() {")
+			e.P("used = append(used, ", strconv.Quote(alias.JSONName), ")")
+			e.P("if ", reflectPackage.Ident("ValueOf"), "(m.", f.Name, ").IsZero() {")
+			e.P("m.", f.Name, " = m.", alias.Name)
+			e.P("}")
+			e.P("var zero ", e.typ(alias.GoType))
+			e.P("m.", alias.Name, " = zero")
+			e.P("}")
+		}
+	}
+	e.P("return used")
+	e.P("}")
+	e.P()
+}
+
+// sortedOneOfs returns the one-of groups of a model sorted by name.
+func sortedOneOfs(msg *Message) []*OneOf {
+	names := make([]string, 0, len(msg.OneOfs))
+	for name := range msg.OneOfs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groups := make([]*OneOf, len(names))
+	for i, name := range names {
+		groups[i] = msg.OneOfs[name]
+	}
+	return groups
+}
+
+// resolve writes the `Resolve` method of a model, which Huma calls after
+// parsing input, and the `resolveGenerated` method it uses if needed.
+func (e *emitter) resolve(msg *Message) {
+	ctx := humaPackage.Ident("Context")
+	request := httpPackage.Ident("Request")
+	embedded := []*Field{}
+	for _, f := range msg.Fields {
+		if f.IsEmbedded {
+			embedded = append(embedded, f)
+		}
+	}
+	generated := len(msg.OneOfs) > 0 || msg.HasAliases || msg.HasValidators || len(embedded) > 0
+
+	if generated {
+		e.P("// resolveGenerated runs the generated validation for the model.")
+		e.P("func (m *", msg.Name, ") resolveGenerated(ctx ", ctx, ", r *", request, ") {")
+		if len(embedded) > 0 {
+			e.P("// Huma skips flattened messages as they are unexported, so they are")
+			e.P("// resolved here with locations relative to this model.")
+			for _, f := range embedded {
+				e.P("m.", f.Name, ".Resolve(ctx, r)")
+			}
+		}
+		if msg.HasAliases {
+			if e.file.AliasHook != "" {
+				e.P("// Report deprecated field names, see the `json_alias_hook` annotation.")
+				e.P("for _, alias := range m.ResolveJSONAliases() {")
+				e.P(e.typ(e.file.AliasHook), "(ctx, ", strconv.Quote(msg.Name), ", alias)")
+				e.P("}")
+			} else {
+				e.P("m.ResolveJSONAliases()")
+			}
+			for _, f := range msg.Fields {
+				if !f.Validation.IsRequired || len(f.Aliases) == 0 {
+					continue
+				}
+				e.P("if ", reflectPackage.Ident("ValueOf"), "(m.", f.Name, ").IsZero() {")
+				e.P("ctx.AddError(&", humaPackage.Ident("ErrorDetail"), "{")
+				e.P(`Message: "Field '`, f.JSONName, `' is required in '`, msg.Name, `'",`)
+				e.P("Location: ", strconv.Quote(f.JSONName), ",")
+				e.P("})")
+				e.P("}")
+			}
+		}
+
+		for _, f := range msg.Fields {
+			if f.Validator == "" {
+				continue
+			}
+			e.P("if !", reflectPackage.Ident("ValueOf"), "(m.", f.Name, ").IsZero() {")
+			e.P("if err := ", e.typ(f.Validator), "(m.", f.Name, "); err != nil {")
+			e.P("ctx.AddError(&", humaPackage.Ident("ErrorDetail"), "{")
+			e.P("Message: err.Error(),")
+			e.P("Location: ", strconv.Quote(f.JSONName), ",")
+			e.P("Value: m.", f.Name, ",")
+			e.P("})")
+			e.P("}")
+			e.P("}")
+		}
+
+		for _, group := range sortedOneOfs(msg) {
+			names := make([]string, len(group.Fields))
+			for i, f := range group.Fields {
+				names[i] = "'" + f.JSONName + "'"
+			}
+
+			e.P("{")
+			e.P("seen := []string{}")
+			for _, f := range group.Fields {
+				e.P("if !", reflectPackage.Ident("ValueOf"), "(m.", f.Name, ").IsZero() {")
+				e.P("seen = append(seen, ", strconv.Quote(f.JSONName), ")")
+				e.P("}")
+			}
+			e.P("if len(seen) > 1 {")
+			e.P("ctx.AddError(&", humaPackage.Ident("ErrorDetail"), "{")
+			e.P(`Message: "Only one of [`, strings.Join(names, ", "), `] allowed in '`, msg.Name, `'",`)
+			e.P("Location: seen[0],")
+			e.P("Value: ", stringsPackage.Ident("Join"), `(seen, ", "),`)
+			e.P("})")
+			e.P("}")
+			if d := group.Discriminator; d != nil {
+				e.P(`if len(seen) == 1 && m.`, d.Name, ` != "" && m.`, d.Name, ` != seen[0] {`)
+				e.P("ctx.AddError(&", humaPackage.Ident("ErrorDetail"), "{")
+				e.P(`Message: "Discriminator '`, d.JSONName, `' must name the set field '" + seen[0] + "' in '`, msg.Name, `'",`)
+				e.P("Location: ", strconv.Quote(d.JSONName), ",")
+				e.P("Value: m.", d.Name, ",")
+				e.P("})")
+				e.P("}")
+			}
+			e.P("}")
+		}
+		e.P("}")
+		e.P()
+	}
+
+	e.P("// Resolve runs the generated validation for the model, followed by a")
+	e.P("// `ResolveCustom(ctx huma.Context, r *http.Request)` method if the model")
+	e.P("// has one for hand-written validation.")
+	e.P("// Error locations are relative to the model, and are prefixed by Huma with")
+	e.P("// the path to the model within the request.")
+	e.P("func (m *", msg.Name, ") Resolve(ctx ", ctx, ", r *", request, ") {")
+	if generated {
+		e.P("m.resolveGenerated(ctx, r)")
+		e.P()
+	}
+	e.P("if c, ok := interface{}(m).(interface {")
+	e.P("ResolveCustom(ctx ", ctx, ", r *", request, ")")
+	e.P("}); ok {")
+	e.P("c.ResolveCustom(ctx, r)")
+	e.P("}")
+	e.P("}")
+	e.P()
+}
+
+// isMessage returns whether a field holds generated models, which have
+// their own methods to call.
+func isMessage(f *Field) bool {
+	return !f.IsPrimitive && f.Enum == nil && !f.IsCustom && f.GoType != "*time.Time"
+}
+
+// redact writes the `Redact` method of a model, which zeroes out fields the
+// caller may not see and redacts nested models.
+func (e *emitter) redact(msg *Message) {
+	e.P("// Redact zeroes out fields which require a scope the caller does not have,")
+	e.P("// including those of nested messages.")
+	e.P("func (m *", msg.Name, ") Redact(scopes []string) {")
+	if msg.HasScopes {
+		e.P("hasScope := func(scope string) bool {")
+		e.P("for _, s := range scopes {")
+		e.P("if s == scope {")
+		e.P("return true")
+		e.P("}")
+		e.P("}")
+		e.P("return false")
+		e.P("}")
+		e.P()
+	}
+
+	for _, f := range msg.Fields {
+		if f.RequiredScope != "" {
+			e.P("if !hasScope(", strconv.Quote(f.RequiredScope), ") {")
+			e.P("var zero ", e.typ(f.GoType))
+			e.P("m.", f.Name, " = zero")
+			e.P("}")
+		}
+
+		switch {
+		case f.IsEmbedded:
+			e.P("m.", f.Name, ".Redact(scopes)")
+		case !isMessage(f):
+		case f.IsRepeated || f.IsMap:
+			e.P("for _, v := range m.", f.Name, " {")
+			e.P("if v != nil {")
+			e.P("v.Redact(scopes)")
+			e.P("}")
+			e.P("}")
+		default:
+			e.P("if m.", f.Name, " != nil {")
+			e.P("m.", f.Name, ".Redact(scopes)")
+			e.P("}")
+		}
+	}
+	e.P("}")
+	e.P()
+}
+
+// stringers writes the `String` and `GoString` methods of a model, which
+// mask sensitive fields.
+func (e *emitter) stringers(msg *Message) {
+	plain := make([]string, len(msg.Fields))
+	gosyntax := make([]string, len(msg.Fields))
+	args := ""
+	for i, f := range msg.Fields {
+		if f.IsSensitive {
+			plain[i] = f.Name + ":***"
+			gosyntax[i] = f.Name + `:\"***\"`
+			continue
+		}
+		label := f.Name
+		if f.IsEmbedded {
+			// Flattened messages are labelled by their field rather than alias.
+			label = f.ProtoGoName
+		}
+		plain[i] = label + ":%v"
+		gosyntax[i] = label + ":%#v"
+		args += ", m." + f.Name
+	}
+
+	e.P("// String returns a representation of the model for logging with sensitive")
+	e.P("// fields masked.")
+	e.P("func (m ", msg.Name, ") String() string {")
+	e.P("return ", fmtPackage.Ident("Sprintf"), `("{`, strings.Join(plain, " "), `}"`, args, ")")
+	e.P("}")
+	e.P()
+
+	e.P("// GoString returns a Go syntax representation of the model for logging with")
+	e.P("// sensitive fields masked.")
+	e.P("func (m ", msg.Name, ") GoString() string {")
+	e.P("return ", fmtPackage.Ident("Sprintf"), `("`, e.file.GoPackage.Name, ".", msg.Name, "{", strings.Join(gosyntax, ", "), `}"`, args, ")")
+	e.P("}")
+	e.P()
+}
+
+// fail writes a check returning a wrapped error from a conversion method if
+// the preceding call failed. This is only used for models with fields that
+// may fail.
+func (e *emitter) fail(f *Field) {
+	e.P("if err != nil {")
+	e.P("return nil, ", fmtPackage.Ident("Errorf"), `("`, f.JSONName, `: %w", err)`)
+	e.P("}")
+}
+
+// elem returns the model type of the items of a repeated or map field, e.g.
+// `package2huma.Message` for `[]*package2huma.Message`.
+func elem(goType string) string {
+	goType = strings.TrimPrefix(goType, "[]")
+	goType = strings.TrimPrefix(goType, "map[string]")
+	return strings.TrimPrefix(goType, "*")
+}
+
+// fieldFromProto writes the conversion of a field from its protobuf value,
+// where `proto` is the variable holding the protobuf message or one-of member.
+func (e *emitter) fieldFromProto(proto string, f *Field) {
+	value := proto + "." + f.ProtoGoName
+
+	switch {
+	case f.IsWrapper:
+		e.P("if ", value, " != nil {")
+		e.P("m.", f.Name, " = &", e.typ(f.GoType[1:]), "{}")
+		if f.HasErrors {
+			e.P("_, err := m.", f.Name, ".FromProto(", proto, ")")
+			e.fail(f)
+		} else {
+			e.P("m.", f.Name, ".FromProto(", proto, ")")
+		}
+		e.P("}")
+	case f.IsEmbedded:
+		e.P("if ", value, " != nil {")
+		if f.HasErrors {
+			e.P("_, err := m.", f.Name, ".FromProto(", value, ")")
+			e.fail(f)
+		} else {
+			e.P("m.", f.Name, ".FromProto(", value, ")")
+		}
+		e.P("}")
+	case f.IsCustom && f.IsRepeated:
+		e.P("{")
+		e.P("tmp := ", e.typ(f.GoType), "{}")
+		e.P("for _, i := range ", value, " {")
+		if f.FromProtoFunc != "" {
+			e.P("v, err := ", e.typ(f.FromProtoFunc), "(i)")
+			e.fail(f)
+			e.P("tmp = append(tmp, v)")
+		} else {
+			e.P("tmp = append(tmp, (", e.typ(f.GoType[2:]), ")(i))")
+		}
+		e.P("}")
+		e.P("m.", f.Name, " = tmp")
+		e.P("}")
+	case f.IsCustom && f.FromProtoFunc != "":
+		e.P("{")
+		e.P("v, err := ", e.typ(f.FromProtoFunc), "(", value, ")")
+		e.fail(f)
+		e.P("m.", f.Name, " = v")
+		e.P("}")
+	case f.IsCustom:
+		e.P("m.", f.Name, " = (", e.typ(f.GoType), ")(", value, ")")
+	case f.GoType == "*time.Time":
+		e.P("if ", value, " != nil {")
+		e.P("t := ", value, ".AsTime()")
+		e.P("m.", f.Name, " = &t")
+		e.P("}")
+	case f.IsPrimitive:
+		e.P("m.", f.Name, " = ", value)
+	case f.IsRepeated:
+		e.P("{")
+		e.P("tmp := ", e.typ(f.GoType), "{}")
+		e.P("for _, i := range ", value, " {")
+		if f.Enum != nil {
+			e.P("if v, ok := ", e.typ(f.GoType[2:]+"NamesMap"), "[i]; ok {")
+			e.P("tmp = append(tmp, v)")
+			e.P("}")
+		} else {
+			e.P("if i == nil {")
+			e.P("continue")
+			e.P("}")
+			if f.HasErrors {
+				e.P("v, err := (&", e.typ(elem(f.GoType)), "{}).FromProto(i)")
+				e.fail(f)
+				e.P("tmp = append(tmp, v)")
+			} else {
+				e.P("tmp = append(tmp, (&", e.typ(elem(f.GoType)), "{}).FromProto(i))")
+			}
+		}
+		e.P("}")
+		e.P("m.", f.Name, " = tmp")
+		e.P("}")
+	case f.Enum != nil:
+		e.P("if v, ok := ", e.typ(f.GoType+"NamesMap"), "[", value, "]; ok {")
+		e.P("m.", f.Name, " = v")
+		e.P("}")
+	case f.IsMap:
+		e.P("if ", value, " != nil {")
+		e.P("if m.", f.Name, " == nil {")
+		e.P("m.", f.Name, " = ", e.typ(f.GoType), "{}")
+		e.P("}")
+		e.P("for k, v := range ", value, " {")
+		if f.HasErrors {
+			e.P("item, err := (&", e.typ(elem(f.GoType)), "{}).FromProto(v)")
+			e.fail(f)
+			e.P("m.", f.Name, "[k] = item")
+		} else {
+			e.P("m.", f.Name, "[k] = (&", e.typ(elem(f.GoType)), "{}).FromProto(v)")
+		}
+		e.P("}")
+		e.P("}")
+	default:
+		e.P("if ", value, " != nil {")
+		e.P("if m.", f.Name, " == nil {")
+		e.P("m.", f.Name, " = &", e.typ(elem(f.GoType)), "{}")
+		e.P("}")
+		if f.HasErrors {
+			e.P("_, err := m.", f.Name, ".FromProto(", value, ")")
+			e.fail(f)
+		} else {
+			e.P("m.", f.Name, ".FromProto(", value, ")")
+		}
+		e.P("}")
+	}
+}
+
+// fromProto writes the `FromProto` method of a model.
+func (e *emitter) fromProto(msg *Message) {
+	protoMsg := e.proto(msg.ProtoGoName)
+
+	e.P("// FromProto converts a proto message to the Huma representation.")
+	if msg.HasErrors {
+		e.P("func (m *", msg.Name, ") FromProto(proto *", protoMsg, ") (*", msg.Name, ", error) {")
+	} else {
+		e.P("func (m *", msg.Name, ") FromProto(proto *", protoMsg, ") *", msg.Name, " {")
+	}
+
+	for _, f := range msg.Fields {
+		if f.OneOf == "" && !f.IsDiscriminator {
+			e.fieldFromProto("proto", f)
+		}
+	}
+
+	for _, group := range sortedOneOfs(msg) {
+		e.P()
+		e.P("switch oneof := proto.", group.Name, ".(type) {")
+		for _, f := range group.Fields {
+			e.P("case *", e.proto(msg.ProtoGoName+"_"+f.ProtoGoName), ":")
+			e.fieldFromProto("oneof", f)
+			if d := group.Discriminator; d != nil {
+				e.P("m.", d.Name, " = ", strconv.Quote(f.JSONName))
+			}
+		}
+		e.P("}")
+	}
+
+	e.P()
+	if msg.HasErrors {
+		e.P("return m, nil")
+	} else {
+		e.P("return m")
+	}
+	e.P("}")
+	e.P()
+}
+
+// oneOfSet sets the protobuf one-of field to the member which contains the
+// value. It does nothing unless converting a one-of member.
+func (e *emitter) oneOfSet(proto string, f *Field) {
+	if proto == "oneof" {
+		e.P("proto.", f.OneOf, " = oneof")
+	}
+}
+
+// oneOfSetNonZero sets the protobuf one-of field if the value of a primitive
+// or custom type member isn't zero.
+func (e *emitter) oneOfSetNonZero(proto string, f *Field) {
+	if proto == "oneof" {
+		e.P("if !", reflectPackage.Ident("ValueOf"), "(m.", f.Name, ").IsZero() {")
+		e.oneOfSet(proto, f)
+		e.P("}")
+	}
+}
+
+// fieldToProto writes the conversion of a field to its protobuf value, where
+// `proto` is the variable holding the protobuf message or one-of member.
+func (e *emitter) fieldToProto(proto string, f *Field) {
+	value := proto + "." + f.ProtoGoName
+
+	switch {
+	case f.IsWrapper:
+		e.P("if m.", f.Name, " != nil {")
+		if f.HasErrors {
+			e.P("_, err := m.", f.Name, ".ToProto(", proto, ")")
+			e.fail(f)
+		} else {
+			e.P("m.", f.Name, ".ToProto(", proto, ")")
+		}
+		e.P("}")
+	case f.IsEmbedded:
+		e.P("if !", reflectPackage.Ident("ValueOf"), "(m.", f.Name, ").IsZero() {")
+		if f.HasErrors {
+			e.P("v, err := m.", f.Name, ".ToProto(", value, ")")
+			e.fail(f)
+			e.P(value, " = v")
+		} else {
+			e.P(value, " = m.", f.Name, ".ToProto(", value, ")")
+		}
+		e.P("}")
+	case f.IsCustom && f.IsRepeated:
+		e.P("{")
+		e.P("tmp := ", e.typ(f.ProtoGoType), "{}")
+		e.P("for _, i := range m.", f.Name, " {")
+		if f.ToProtoFunc != "" {
+			e.P("v, err := ", e.typ(f.ToProtoFunc), "(i)")
+			e.fail(f)
+			e.P("tmp = append(tmp, v)")
+		} else {
+			e.P("tmp = append(tmp, ", e.typ(f.ProtoGoType[2:]), "(i))")
+		}
+		e.P("}")
+		e.P(value, " = tmp")
+		e.P("}")
+	case f.IsCustom:
+		if f.ToProtoFunc != "" {
+			e.P("{")
+			e.P("v, err := ", e.typ(f.ToProtoFunc), "(m.", f.Name, ")")
+			e.fail(f)
+			e.P(value, " = v")
+			e.P("}")
+		} else {
+			e.P(value, " = ", e.typ(f.ProtoGoType), "(m.", f.Name, ")")
+		}
+		e.oneOfSetNonZero(proto, f)
+	case f.GoType == "*time.Time":
+		e.P("if m.", f.Name, " != nil && !m.", f.Name, ".IsZero() {")
+		e.P(value, " = ", timestamppbPackage.Ident("New"), "(*m.", f.Name, ")")
+		e.oneOfSet(proto, f)
+		e.P("}")
+	case f.IsPrimitive:
+		e.P(value, " = m.", f.Name)
+		e.oneOfSetNonZero(proto, f)
+	case f.IsRepeated:
+		// A one-of can't be repeated, so there is no need to set it here.
+		e.P("{")
+		e.P("tmp := ", e.typ(f.ProtoGoType), "{}")
+		e.P("for _, i := range m.", f.Name, " {")
+		if f.Enum != nil {
+			e.P("if v, ok := ", e.typ(f.GoType[2:]+"ValuesMap"), "[i]; ok {")
+			e.P("tmp = append(tmp, v)")
+			e.P("}")
+		} else {
+			e.P("if i == nil {")
+			e.P("continue")
+			e.P("}")
+			if f.HasErrors {
+				e.P("v, err := i.ToProto(nil)")
+				e.fail(f)
+				e.P("tmp = append(tmp, v)")
+			} else {
+				e.P("tmp = append(tmp, i.ToProto(nil))")
+			}
+		}
+		e.P("}")
+		e.P(value, " = tmp")
+		e.P("}")
+	case f.Enum != nil:
+		e.P("if v, ok := ", e.typ(f.GoType+"ValuesMap"), "[m.", f.Name, "]; ok {")
+		e.P(value, " = v")
+		e.oneOfSet(proto, f)
+		e.P("}")
+	case f.IsMap:
+		e.P("if m.", f.Name, " != nil {")
+		e.P("if ", value, " == nil {")
+		e.P(value, " = ", e.typ(f.ProtoGoType), "{}")
+		e.P("}")
+		e.P("for k, v := range m.", f.Name, " {")
+		if f.HasErrors {
+			e.P("item, err := v.ToProto(", value, "[k])")
+			e.fail(f)
+			e.P(value, "[k] = item")
+		} else {
+			e.P(value, "[k] = v.ToProto(", value, "[k])")
+		}
+		e.P("}")
+		e.oneOfSet(proto, f)
+		e.P("}")
+	default:
+		e.P("if m.", f.Name, " != nil {")
+		if f.HasErrors {
+			e.P("v, err := m.", f.Name, ".ToProto(", value, ")")
+			e.fail(f)
+			e.P(value, " = v")
+		} else {
+			e.P(value, " = m.", f.Name, ".ToProto(", value, ")")
+		}
+		e.oneOfSet(proto, f)
+		e.P("}")
+	}
+}
+
+// toProto writes the `ToProto` method of a model.
+func (e *emitter) toProto(msg *Message) {
+	protoMsg := e.proto(msg.ProtoGoName)
+
+	e.P("// ToProto converts a Huma representation to a proto message.")
+	if msg.HasErrors {
+		e.P("func (m *", msg.Name, ") ToProto(proto *", protoMsg, ") (*", protoMsg, ", error) {")
+	} else {
+		e.P("func (m *", msg.Name, ") ToProto(proto *", protoMsg, ") *", protoMsg, " {")
+	}
+	e.P("if proto == nil {")
+	e.P("proto = &", protoMsg, "{}")
+	e.P("}")
+	e.P()
+
+	for _, f := range msg.Fields {
+		if f.OneOf != "" {
+			e.P("{")
+			e.P("oneof := &", e.proto(msg.ProtoGoName+"_"+f.ProtoGoName), "{}")
+			e.fieldToProto("oneof", f)
+			e.P("}")
+		} else if !f.IsDiscriminator {
+			e.fieldToProto("proto", f)
+		}
+	}
+
+	for _, group := range sortedOneOfs(msg) {
+		d := group.Discriminator
+		if d == nil {
+			continue
+		}
+
+		e.P()
+		e.P("// The discriminator sets members with zero values.")
+		e.P("if proto.", group.Name, " == nil {")
+		e.P("switch m.", d.Name, " {")
+		for _, f := range group.Fields {
+			e.P("case ", strconv.Quote(f.JSONName), ":")
+			e.P("proto.", group.Name, " = &", e.proto(msg.ProtoGoName+"_"+f.ProtoGoName), "{}")
+		}
+		e.P("}")
+		e.P("}")
+	}
+
+	e.P()
+	if msg.HasErrors {
+		e.P("return proto, nil")
+	} else {
+		e.P("return proto")
+	}
+	e.P("}")
+	e.P()
+}
+
+// emitSlog writes `slog.LogValuer` implementations for the models of a file.
+// These are written to a separate file with a build constraint, as the
+// `log/slog` package requires Go 1.21.
+func emitSlog(plugin *protogen.Plugin, tFile *File, filename string) {
+	e := newEmitter(plugin, tFile, filename)
+	e.header()
+	e.P()
+	e.P("//go:build go1.21")
+	e.P("// +build go1.21")
+	e.P()
+	e.P("package ", tFile.GoPackage.Name)
+	e.P()
+
+	for _, msg := range tFile.Messages {
+		e.P("// LogValue returns a structured representation of the model for logging")
+		e.P("// with sensitive fields masked.")
+		e.P("func (m ", msg.Name, ") LogValue() ", slogPackage.Ident("Value"), " {")
+		e.P("attrs := []", slogPackage.Ident("Attr"), "{}")
+		for _, f := range msg.Fields {
+			switch {
+			case f.IsSensitive:
+				e.P("attrs = append(attrs, ", slogPackage.Ident("String"), "(", strconv.Quote(f.JSONName), `, "***"))`)
+			case f.IsEmbedded:
+				e.P("// Flattened fields are inlined into the group via an empty key.")
+				e.P("attrs = append(attrs, ", slogPackage.Ident("Any"), `("", m.`, f.Name, "))")
+			case !f.IsPrimitive && f.Enum == nil && !f.IsCustom && !f.IsRepeated && !f.IsMap:
+				e.P("if m.", f.Name, " != nil {")
+				e.P("attrs = append(attrs, ", slogPackage.Ident("Any"), "(", strconv.Quote(f.JSONName), ", m.", f.Name, "))")
+				e.P("}")
+			case isMessage(f) && (f.IsRepeated || f.IsMap):
+				// Handlers marshal slices and maps as a whole, which would skip
+				// the `LogValue` of each model, so they are logged as groups.
+				key := []interface{}{fmtPackage.Ident("Sprint"), "(k)"}
+				if f.IsRepeated {
+					key = []interface{}{strconvPackage.Ident("Itoa"), "(k)"}
+				} else if strings.HasPrefix(f.GoType, "map[string]") {
+					key = []interface{}{"k"}
+				}
+				e.P("if m.", f.Name, " != nil {")
+				e.P("group := make([]", slogPackage.Ident("Attr"), ", 0, len(m.", f.Name, "))")
+				e.P("for k, v := range m.", f.Name, " {")
+				e.P("if v != nil {")
+				e.P(append(append([]interface{}{"group = append(group, ", slogPackage.Ident("Any"), "("}, key...), ", v))")...)
+				e.P("}")
+				e.P("}")
+				e.P("attrs = append(attrs, ", slogPackage.Ident("Attr"), "{Key: ", strconv.Quote(f.JSONName), ", Value: ", slogPackage.Ident("GroupValue"), "(group...)})")
+				e.P("}")
+			default:
+				e.P("attrs = append(attrs, ", slogPackage.Ident("Any"), "(", strconv.Quote(f.JSONName), ", m.", f.Name, "))")
+			}
+		}
+		e.P("return ", slogPackage.Ident("GroupValue"), "(attrs...)")
+		e.P("}")
+		e.P()
+	}
+
+	e.check()
+}
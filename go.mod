@@ -3,10 +3,9 @@ module github.com/istreamlabs/protoc-gen-huma
 go 1.15
 
 require (
-	github.com/Jeffail/gabs/v2 v2.6.1 // indirect
+	github.com/Jeffail/gabs/v2 v2.6.1
 	github.com/danielgtaylor/casing v0.0.0-20210126043903-4e55e6373ac3
 	github.com/danielgtaylor/huma v1.0.0
-	github.com/davecgh/go-spew v1.1.1
 	github.com/envoyproxy/protoc-gen-validate v0.4.1
 	github.com/fatih/color v1.13.0 // indirect
 	github.com/flosch/pongo2 v0.0.0-20200913210552-0d938eb266f3
@@ -0,0 +1,57 @@
+// Package exampleconv provides custom Go types and converter functions used
+// by the example protobuf definitions to test the `go_type`, `from_proto`,
+// `to_proto`, `validator`, and `json_alias_hook` annotations.
+package exampleconv
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma"
+)
+
+// ErrInvalidSlug is returned when a value is not a valid slug.
+var ErrInvalidSlug = errors.New("invalid slug")
+
+// Slug is a lowercase identifier without whitespace.
+type Slug string
+
+// SlugFromProto converts a protobuf string to a slug.
+func SlugFromProto(value string) (Slug, error) {
+	if value != strings.ToLower(value) || strings.ContainsAny(value, " \t\n") {
+		return "", ErrInvalidSlug
+	}
+	return Slug(value), nil
+}
+
+// SlugToProto converts a slug to a protobuf string.
+func SlugToProto(value Slug) (string, error) {
+	return string(value), nil
+}
+
+// DurationToProto converts a duration to a protobuf string like `1m30s`.
+func DurationToProto(value time.Duration) (string, error) {
+	return value.String(), nil
+}
+
+// ErrUnknownCountry is returned when a country code is not in the catalog.
+var ErrUnknownCountry = errors.New("unknown country code")
+
+// ValidateCountry checks that a country code exists in the catalog.
+func ValidateCountry(value string) error {
+	switch value {
+	case "DE", "GB", "US":
+		return nil
+	}
+	return ErrUnknownCountry
+}
+
+// ReportedAliases lists the deprecated JSON field aliases passed to
+// `ReportAlias`, e.g. `Message.title`.
+var ReportedAliases []string
+
+// ReportAlias records the use of a deprecated JSON field alias.
+func ReportAlias(ctx huma.Context, model, alias string) {
+	ReportedAliases = append(ReportedAliases, model+"."+alias)
+}
@@ -1,20 +1,24 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/istreamlabs/protoc-gen-huma/annotation"
 
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 
@@ -95,9 +99,128 @@ func getComments(file *descriptor.FileDescriptorProto, path []int32) string {
 	return ""
 }
 
+// enumLabel returns the JSON representation of an enum value label using the
+// given casing strategy.
+func enumLabel(label string, c annotation.Casing) string {
+	switch c {
+	case annotation.Casing_CASING_LOWER:
+		return casing.Snake(label, strings.ToLower)
+	case annotation.Casing_CASING_KEBAB:
+		return casing.Kebab(label, strings.ToLower)
+	case annotation.Casing_CASING_CAMEL:
+		return casing.LowerCamel(label, strings.ToLower)
+	}
+	return label
+}
+
+// enumBoolOption returns the value of a boolean enum option, falling back to
+// the corresponding file-level option if the enum doesn't set it.
+func enumBoolOption(file *descriptor.FileDescriptorProto, e *descriptor.EnumDescriptorProto, fileExt, enumExt protoreflect.ExtensionType) bool {
+	if proto.HasExtension(e.GetOptions(), enumExt) {
+		return proto.GetExtension(e.GetOptions(), enumExt).(bool)
+	}
+	return proto.GetExtension(file.GetOptions(), fileExt).(bool)
+}
+
+// enumPrefix returns the value prefix to strip from an enum, if enabled via
+// the enum or file options. For example, `FRUITS_` for the `Fruits` enum.
+func enumPrefix(file *descriptor.FileDescriptorProto, e *descriptor.EnumDescriptorProto) string {
+	if !enumBoolOption(file, e, annotation.E_StripEnumPrefix, annotation.E_StripPrefix) {
+		return ""
+	}
+
+	return casing.Snake(e.GetName(), strings.ToUpper) + "_"
+}
+
+// buildEnumValues converts the enum's values, skipping excluded ones and
+// removing `strip` from the start of each value name if present.
+func buildEnumValues(file *descriptor.FileDescriptorProto, path []int32, i int, e *descriptor.EnumDescriptorProto, strip string) []EnumValue {
+	values := []EnumValue{}
+	labelCasing := proto.GetExtension(e.GetOptions(), annotation.E_Casing).(annotation.Casing)
+
+	// The zero value is usually `*_UNSPECIFIED`, which is better represented
+	// as an absent field than as a value clients may send.
+	excludeZero := enumBoolOption(file, e, annotation.E_ExcludeEnumZero, annotation.E_ExcludeZero)
+
+	// Aliased enums (`allow_alias`) have several values sharing a number. Only
+	// one of them can be used when converting from protobuf, so keep track of
+	// the canonical value for each number. The first one wins unless another
+	// value has been explicitly marked as canonical.
+	canonical := map[int32]int{}
+	explicit := map[int32]bool{}
+
+	for j, v := range e.GetValue() {
+		if e := proto.GetExtension(v.GetOptions(), annotation.E_Exclude).(bool); e {
+			// Skip this enum value!
+			continue
+		}
+
+		if excludeZero && v.GetNumber() == 0 {
+			continue
+		}
+
+		if _, ok := canonical[v.GetNumber()]; !ok {
+			canonical[v.GetNumber()] = len(values)
+		}
+		if proto.GetExtension(v.GetOptions(), annotation.E_Canonical).(bool) && !explicit[v.GetNumber()] {
+			canonical[v.GetNumber()] = len(values)
+			explicit[v.GetNumber()] = true
+		}
+
+		enumValuePath := append(append([]int32{}, path...), int32(i), 2, int32(j))
+
+		name := v.GetName()
+		if strip != "" && strings.HasPrefix(name, strip) && len(name) > len(strip) {
+			name = name[len(strip):]
+		}
+
+		// The label is used for the JSON representation, the generated maps and
+		// any validation, so an explicit override always wins over casing.
+		label := enumLabel(name, labelCasing)
+		if l := proto.GetExtension(v.GetOptions(), annotation.E_Label).(string); l != "" {
+			label = l
+		}
+
+		values = append(values, EnumValue{
+			Path:    enumValuePath,
+			Name:    goCase(name),
+			Label:   label,
+			Value:   v.GetNumber(),
+			Comment: getComments(file, enumValuePath),
+		})
+	}
+
+	for idx := range values {
+		values[idx].IsAlias = canonical[values[idx].Value] != idx
+	}
+
+	return values
+}
+
+// enumCollision returns the first value whose Go name or label is already used
+// by another value of the enum, along with the duplicate name or label. Labels
+// of numeric enums aren't used in the generated code so they may repeat.
+func enumCollision(enum *Enum) (*EnumValue, string) {
+	names := map[string]bool{}
+	labels := map[string]bool{}
+	for i := range enum.Values {
+		v := &enum.Values[i]
+		if names[v.Name] {
+			return v, v.Name
+		}
+		if !enum.IsNumeric && labels[v.Label] {
+			return v, v.Label
+		}
+		names[v.Name] = true
+		labels[v.Label] = true
+	}
+	return nil, ""
+}
+
 // buildEnum builds a model of the enum with filtered / converted values
-// and the Huma naming scheme.
-func buildEnum(file *descriptor.FileDescriptorProto, prefix string, path []int32, i int, e *descriptor.EnumDescriptorProto) *Enum {
+// and the Huma naming scheme. Enums from dependencies which aren't being
+// generated only get warnings, so they can't fail an unrelated file.
+func buildEnum(file *descriptor.FileDescriptorProto, prefix string, path []int32, i int, e *descriptor.EnumDescriptorProto, generate bool) *Enum {
 	prefix = stripPkg(prefix)
 
 	// If nested, prefix will be set with the outer name. We append to it below.
@@ -110,24 +233,31 @@ func buildEnum(file *descriptor.FileDescriptorProto, prefix string, path []int32
 	tEnum := &Enum{
 		Name:        goCase(prefix + " " + e.GetName()),
 		ProtoGoName: p + casing.Camel(e.GetName(), casing.Identity),
-		Values:      []EnumValue{},
 		Comment:     getComments(file, enumPath),
+		IsNumeric:   enumBoolOption(file, e, annotation.E_NumericEnums, annotation.E_Numeric),
 	}
 
-	for j, v := range e.GetValue() {
-		if e := proto.GetExtension(v.GetOptions(), annotation.E_Exclude).(bool); e {
-			// Skip this enum value!
-			continue
-		}
+	strip := enumPrefix(file, e)
+	tEnum.Values = buildEnumValues(file, path, i, e, strip)
 
-		enumValuePath := append(append([]int32{}, path...), int32(i), 2, int32(j))
+	if strip != "" {
+		// Stripping the prefix may make values ambiguous, e.g. if both
+		// `FRUITS_APPLE` and `APPLE` are defined. Report it and fall back to the
+		// original names so the generated code still compiles.
+		if v, dupe := enumCollision(tEnum); v != nil {
+			warnf(file, enumPath, "Stripping prefix %s from enum %s results in duplicate value %s, keeping original value names", strip, e.GetName(), dupe)
+			tEnum.Values = buildEnumValues(file, path, i, e, "")
+		}
+	}
 
-		tEnum.Values = append(tEnum.Values, EnumValue{
-			Name:    goCase(v.GetName()),
-			Label:   v.GetName(),
-			Value:   v.GetNumber(),
-			Comment: getComments(file, enumValuePath),
-		})
+	// Labels may still collide due to casing or explicit overrides, which
+	// would generate duplicate map keys.
+	if v, dupe := enumCollision(tEnum); v != nil {
+		if generate {
+			errorf(file, v.Path, "Enum %s has duplicate value %s, change its label or casing", e.GetName(), dupe)
+		} else {
+			warnf(file, v.Path, "Enum %s has duplicate value %s, change its label or casing", e.GetName(), dupe)
+		}
 	}
 
 	return tEnum
@@ -135,7 +265,7 @@ func buildEnum(file *descriptor.FileDescriptorProto, prefix string, path []int32
 
 // getType returns the Go type, protobuf-generated Go type, whether the type is
 // a primitive or not, and which enum corresponds to the type if any.
-func getType(tFile *File, prefix string, f *descriptorpb.FieldDescriptorProto) (string, string, bool, *Enum) {
+func getType(tFile *File, prefix string, path []int32, f *descriptorpb.FieldDescriptorProto) (string, string, bool, *Enum) {
 	t := ""
 	pt := ""
 	primitive := true
@@ -163,17 +293,8 @@ func getType(tFile *File, prefix string, f *descriptorpb.FieldDescriptorProto) (
 		t = "[]byte"
 	case descriptor.FieldDescriptorProto_TYPE_ENUM:
 		parts := strings.Split(*f.TypeName, ".")
-		prefix := ""
-		if parts[1] != tFile.PackageName {
-			// Cross package import, so modify the name and add the import.
-			prefix += parts[1] + "huma."
-
-			if entry, ok := registry[*f.TypeName]; ok {
-				tFile.Imports[string(entry.file.GoImportPath)+"huma"] = true
-			}
-		}
-		t = prefix + goCase(strings.Join(parts[2:], "_"))
-		pt = parts[1] + "." + strings.Join(parts[2:], "_")
+		t = qualifier(tFile, *f.TypeName) + goCase(strings.Join(parts[2:], "_"))
+		pt = protoPackage(tFile, *f.TypeName) + "." + strings.Join(parts[2:], "_")
 		primitive = false
 
 		if entry, ok := registry[*f.TypeName]; ok {
@@ -181,8 +302,7 @@ func getType(tFile *File, prefix string, f *descriptorpb.FieldDescriptorProto) (
 		}
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
 		if *f.TypeName == ".google.protobuf.Timestamp" {
-			tFile.Imports["time"] = true
-			tFile.Imports["google.golang.org/protobuf/types/known/timestamppb"] = true
+			tFile.Imports["time"] = "time"
 
 			return "*time.Time", "", false, nil
 		}
@@ -194,7 +314,7 @@ func getType(tFile *File, prefix string, f *descriptorpb.FieldDescriptorProto) (
 			if proto := d.descriptor; proto != nil {
 				if proto.Options != nil && proto.Options.MapEntry != nil && *proto.Options.MapEntry {
 					// Field 0 = key, field 1 = value for every generated message.
-					t, pt, primitive, enum = getType(tFile, prefix, proto.Field[1])
+					t, pt, primitive, enum = getType(tFile, prefix, path, proto.Field[1])
 					t = "map[string]" + t
 					pt = "map[string]" + pt
 					return t, pt, primitive, enum
@@ -203,22 +323,11 @@ func getType(tFile *File, prefix string, f *descriptorpb.FieldDescriptorProto) (
 		}
 
 		parts := strings.Split(*f.TypeName, ".")
-
-		prefix := "*"
-		if parts[1] != tFile.PackageName {
-			// Cross package import, so modify the name and add the import.
-			prefix += parts[1] + "huma."
-
-			if entry, ok := registry[*f.TypeName]; ok {
-				tFile.Imports[string(entry.file.GoImportPath)+"huma"] = true
-			}
-		}
-		t = prefix + goCase(parts[2:]...)
-		pt = "*" + parts[1] + "." + strings.Join(parts[2:], "_")
+		t = "*" + qualifier(tFile, *f.TypeName) + goCase(parts[2:]...) + goCase(tFile.Audience)
+		pt = "*" + protoPackage(tFile, *f.TypeName) + "." + strings.Join(parts[2:], "_")
 		primitive = false
 	default:
-		spew.Fdump(os.Stderr, f)
-		panic("Unknown type")
+		errorf(tFile.Proto, path, "Unsupported type %s for field %s", f.GetType(), f.GetName())
 	}
 
 	if pt == "" {
@@ -228,8 +337,58 @@ func getType(tFile *File, prefix string, f *descriptorpb.FieldDescriptorProto) (
 	return t, pt, primitive, enum
 }
 
-// newField makes a field description from a protobuf field.
-func newField(tFile *File, protoMessage *descriptorpb.DescriptorProto, fieldPath []int32, protoField *descriptorpb.FieldDescriptorProto) *Field {
+// qualifier returns the package qualifier for the Huma type of a protobuf
+// type, e.g. `package2huma.`, and adds the import for it. Types generated
+// into the same package as the file have no qualifier. Packages come from the
+// registry so that `M` import path mappings and configured Huma packages are
+// respected.
+func qualifier(tFile *File, typeName string) string {
+	entry, ok := registry[typeName]
+	if !ok {
+		return ""
+	}
+
+	pkg := tFile.Options.GoPackage(entry.file)
+	if pkg.ImportPath == tFile.GoPackage.ImportPath {
+		return ""
+	}
+
+	tFile.Imports[pkg.ImportPath] = pkg.Name
+	return pkg.Name + "."
+}
+
+// protoPackage returns the Go package name of the protobuf-generated code for
+// a protobuf type and adds the import for it, falling back to the protobuf
+// package name.
+func protoPackage(tFile *File, typeName string) string {
+	if entry, ok := registry[typeName]; ok {
+		tFile.Imports[string(entry.file.GoImportPath)] = string(entry.file.GoPackageName)
+		return string(entry.file.GoPackageName)
+	}
+	return strings.Split(typeName, ".")[1]
+}
+
+// isPublic returns whether a field should be exposed in the Huma model for the
+// file's current audience. Public fields are exposed to every audience, while
+// the default audience (an empty string) only gets public fields.
+func isPublic(tFile *File, protoField *descriptorpb.FieldDescriptorProto) bool {
+	if proto.GetExtension(protoField.GetOptions(), annotation.E_Public).(bool) || tFile.Options.AllPublic {
+		return true
+	}
+
+	if tFile.Audience != "" {
+		for _, a := range proto.GetExtension(protoField.GetOptions(), annotation.E_Audience).([]string) {
+			if a == tFile.Audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fieldNames returns the Huma Go and JSON names for a protobuf field.
+func fieldNames(protoField *descriptorpb.FieldDescriptorProto) (string, string) {
 	name := goCase(protoField.GetName())
 	jsName := casing.Snake(protoField.GetJsonName())
 
@@ -241,12 +400,362 @@ func newField(tFile *File, protoMessage *descriptorpb.DescriptorProto, fieldPath
 		jsName = s
 	}
 
+	return name, jsName
+}
+
+// jsonNames returns the JSON field names of the Huma model for a message,
+// including the fields of any flattened sub-messages. The `skip` field is
+// ignored if given.
+func jsonNames(tFile *File, msg *descriptorpb.DescriptorProto, skip *descriptorpb.FieldDescriptorProto) []string {
+	names := []string{}
+	for _, f := range msg.Field {
+		if f == skip || !isPublic(tFile, f) {
+			continue
+		}
+
+		if proto.GetExtension(f.GetOptions(), annotation.E_Flatten).(bool) {
+			if entry, ok := registry[f.GetTypeName()]; ok && entry.descriptor != nil {
+				names = append(names, jsonNames(tFile, entry.descriptor, nil)...)
+				continue
+			}
+		}
+
+		_, jsName := fieldNames(f)
+		names = append(names, jsName)
+	}
+	return names
+}
+
+// takenNames returns the Go and JSON names which a JSON alias of `protoField`
+// must not reuse. These are the names of all public fields of the message and
+// the aliases of fields declared before it, which take precedence.
+func takenNames(tFile *File, msg *descriptorpb.DescriptorProto, protoField *descriptorpb.FieldDescriptorProto) (map[string]bool, map[string]bool) {
+	goNames := map[string]bool{}
+	jsNames := map[string]bool{}
+	for _, name := range jsonNames(tFile, msg, nil) {
+		jsNames[name] = true
+	}
+
+	before := true
+	for _, f := range msg.Field {
+		if f == protoField {
+			before = false
+		}
+		if !isPublic(tFile, f) {
+			continue
+		}
+
+		name, _ := fieldNames(f)
+		goNames[name] = true
+
+		if before {
+			for _, alias := range proto.GetExtension(f.GetOptions(), annotation.E_JsonAlias).([]string) {
+				goNames[goCase("deprecated_"+alias)] = true
+				jsNames[alias] = true
+			}
+		}
+	}
+	return goNames, jsNames
+}
+
+// flatten turns a message field into an embedded struct so its fields are
+// hoisted into the parent's JSON representation. Only singular message fields
+// without JSON name collisions can be flattened.
+func flatten(tFile *File, protoMessage *descriptorpb.DescriptorProto, protoField *descriptorpb.FieldDescriptorProto, f *Field) {
+	entry, ok := registry[protoField.GetTypeName()]
+	if !ok || entry.descriptor == nil || f.IsRepeated || f.IsMap || f.OneOf != "" || f.GoType == "*time.Time" {
+		warnf(tFile.Proto, f.Path, "Only singular message fields can be flattened, cannot flatten %s.%s", protoMessage.GetName(), protoField.GetName())
+		return
+	}
+
+	parent := map[string]bool{}
+	for _, name := range jsonNames(tFile, protoMessage, protoField) {
+		parent[name] = true
+	}
+	for _, name := range jsonNames(tFile, entry.descriptor, nil) {
+		if parent[name] {
+			warnf(tFile.Proto, f.Path, "Flattening %s.%s results in duplicate JSON field %s, keeping it nested", protoMessage.GetName(), protoField.GetName(), name)
+			return
+		}
+	}
+
+	// Embed by value. Embedded fields are hoisted by both the JSON marshaller
+	// and Huma's schema generator, but Huma would resolve them with the Go
+	// field name in error locations. They are embedded via an unexported alias
+	// instead, named after the parent in `processFile`, which Huma skips so the
+	// parent can resolve them.
+	f.IsEmbedded = true
+	f.GoType = strings.TrimPrefix(f.GoType, "*")
+}
+
+// qualify returns the Go identifier for a possibly package-qualified name and
+// adds the package to the file's imports. For example,
+// `github.com/shopspring/decimal.Decimal` becomes `decimal.Decimal`.
+// Unqualified names refer to the generated Huma package. The package name is
+// derived like for `go_package`, which also allows giving it explicitly, e.g.
+// `example.com/go-yaml;yaml.Node`.
+func qualify(tFile *File, name string) string {
+	ptr := ""
+	if strings.HasPrefix(name, "*") {
+		ptr = "*"
+		name = name[1:]
+	}
+
+	dot := strings.LastIndex(name, ".")
+	if dot == -1 {
+		return ptr + name
+	}
+
+	importPath := name[:dot]
+	pkg := defaultPackageName(importPath)
+	if i := strings.Index(importPath, ";"); i >= 0 {
+		importPath, pkg = importPath[:i], importPath[i+1:]
+	}
+	tFile.Imports[importPath] = pkg
+
+	return ptr + pkg + name[dot:]
+}
+
+// customizable returns whether a field can use a custom Go type, i.e. it's a
+// primitive or repeated primitive field.
+func customizable(protoField *descriptorpb.FieldDescriptorProto) bool {
+	switch protoField.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_ENUM, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	}
+	return true
+}
+
+// hasConverter returns whether a field uses custom converter functions, which
+// may return errors. Converters are only applied along with a custom Go type,
+// see `customType`.
+func hasConverter(protoField *descriptorpb.FieldDescriptorProto) bool {
+	if proto.GetExtension(protoField.GetOptions(), annotation.E_GoType).(string) == "" || !customizable(protoField) {
+		return false
+	}
+	return proto.GetExtension(protoField.GetOptions(), annotation.E_FromProto).(string) != "" ||
+		proto.GetExtension(protoField.GetOptions(), annotation.E_ToProto).(string) != ""
+}
+
+// canFail returns whether converting a message type to or from protobuf may
+// fail, i.e. whether it or any message used by its public fields has custom
+// converter functions. Map entries are checked via their value type.
+func canFail(tFile *File, typeName string, visiting map[string]bool) bool {
+	entry, ok := registry[typeName]
+	if !ok || entry.descriptor == nil || visiting[typeName] {
+		return false
+	}
+	visiting[typeName] = true
+	defer delete(visiting, typeName)
+
+	isMapEntry := entry.descriptor.GetOptions().GetMapEntry()
+	for _, f := range entry.descriptor.Field {
+		if !isMapEntry && !isPublic(tFile, f) {
+			continue
+		}
+		if hasConverter(f) {
+			return true
+		}
+		if f.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE && canFail(tFile, f.GetTypeName(), visiting) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// customType applies a custom Go type and its converter functions to a
+// primitive field.
+func customType(tFile *File, protoMessage *descriptorpb.DescriptorProto, protoField *descriptorpb.FieldDescriptorProto, f *Field, goType string) {
+	if !customizable(protoField) {
+		warnf(tFile.Proto, f.Path, "Custom Go types are only supported for primitive and repeated primitive fields, ignoring type and converters for %s.%s", protoMessage.GetName(), protoField.GetName())
+		return
+	}
+
+	f.IsCustom = true
+	f.GoType = qualify(tFile, goType)
+	if f.IsRepeated {
+		f.GoType = "[]" + f.GoType
+	}
+
+	if fn := proto.GetExtension(protoField.GetOptions(), annotation.E_FromProto).(string); fn != "" {
+		f.FromProtoFunc = qualify(tFile, fn)
+	}
+	if fn := proto.GetExtension(protoField.GetOptions(), annotation.E_ToProto).(string); fn != "" {
+		f.ToProtoFunc = qualify(tFile, fn)
+	}
+}
+
+// reservedTags are the struct tags emitted by the plugin itself, see the `tags`
+// template macro. These cannot be set via the `tags` annotation.
+var reservedTags = map[string]bool{
+	"json": true, "enum": true, "minimum": true, "exclusiveMinimum": true,
+	"maximum": true, "exclusiveMaximum": true, "minLength": true,
+	"maxLength": true, "pattern": true, "format": true, "minItems": true,
+	"maxItems": true, "uniqueItems": true, "readOnly": true, "deprecated": true,
+	"multipleOf": true, "example": true, "doc": true,
+}
+
+// tagKeyRegex matches valid struct tag keys.
+var tagKeyRegex = regexp.MustCompile(`^[^\s:"\x60]+$`)
+
+// passthrough adds extra struct tags and JSON Schema extensions to a field
+// from annotations. Invalid or colliding keys are ignored with a warning.
+func passthrough(tFile *File, protoMessage *descriptorpb.DescriptorProto, protoField *descriptorpb.FieldDescriptorProto, f *Field) {
+	name := protoMessage.GetName() + "." + protoField.GetName()
+
+	tags := proto.GetExtension(protoField.GetOptions(), annotation.E_Tags).([]*annotation.Tag)
+	exts := proto.GetExtension(protoField.GetOptions(), annotation.E_Extensions).([]*annotation.Extension)
+	if f.IsEmbedded && (len(tags) > 0 || len(exts) > 0) {
+		warnf(tFile.Proto, f.Path, "Flattened fields cannot have tags or extensions, ignoring them for %s", name)
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		key := tag.GetKey()
+		switch {
+		case !tagKeyRegex.MatchString(key):
+			warnf(tFile.Proto, f.Path, "Invalid struct tag key '%s' for %s", key, name)
+		case reservedTags[key] || seen[key]:
+			warnf(tFile.Proto, f.Path, "Struct tag '%s' for %s collides with another tag, ignoring it", key, name)
+		case strings.Contains(tag.GetValue(), "`"):
+			warnf(tFile.Proto, f.Path, "Struct tag '%s' for %s cannot contain backticks", key, name)
+		default:
+			seen[key] = true
+			f.ExtraTags += " " + key + ":" + strconv.Quote(tag.GetValue())
+		}
+	}
+
+	for _, ext := range exts {
+		key := ext.GetKey()
+		var value interface{}
+		switch {
+		case !strings.HasPrefix(key, "x-"):
+			warnf(tFile.Proto, f.Path, "Schema extension '%s' for %s must start with 'x-'", key, name)
+		case f.Extensions[f.JSONName][key] != "":
+			warnf(tFile.Proto, f.Path, "Schema extension '%s' for %s collides with another extension, ignoring it", key, name)
+		case json.Unmarshal([]byte(ext.GetValue()), &value) != nil:
+			warnf(tFile.Proto, f.Path, "Schema extension '%s' for %s must have a JSON value, got %s", key, name, ext.GetValue())
+		case value == nil:
+			f.setExtension(key, "nil", false)
+		default:
+			f.setExtension(key, fmt.Sprintf("%#v", value), false)
+		}
+	}
+}
+
+// wrapper returns a message holding the members of a `WRAPPED` one-of group,
+// which is nested in the parent message via a new field named after the
+// group. The wrapper converts to and from the parent's protobuf message.
+func wrapper(tMsg *Message, decl *descriptorpb.OneofDescriptorProto, group *OneOf) *Message {
+	w := &Message{
+		Path:        tMsg.Path,
+		Name:        tMsg.Name + goCase(decl.GetName()),
+		ProtoGoName: tMsg.ProtoGoName,
+		Fields:      []*Field{},
+		OneOfs:      map[string]*OneOf{group.Name: group},
+		Comment:     tMsg.Name + goCase(decl.GetName()) + " holds the members of the `" + decl.GetName() + "` one-of group of `" + tMsg.Name + "`.",
+		Extensions:  map[string]map[string]string{},
+	}
+
+	tMsg.Fields = append(tMsg.Fields, &Field{
+		Name:        goCase(decl.GetName()),
+		ProtoGoName: group.Name,
+		JSONName:    casing.Snake(decl.GetName()),
+		GoType:      "*" + w.Name,
+		IsWrapper:   true,
+	})
+
+	return w
+}
+
+// discriminator returns a field naming the set member of a one-of group,
+// which is added to the message. It returns nil if the group can't have one.
+func discriminator(tFile *File, tMsg *Message, msg *descriptorpb.DescriptorProto, declPath []int32, decl *descriptorpb.OneofDescriptorProto, group *OneOf, name string, members []string) *Field {
+	if !group.IsUnion {
+		warnf(tFile.Proto, declPath, "Discriminators require the UNION one-of style, ignoring discriminator for %s.%s", msg.GetName(), decl.GetName())
+		return nil
+	}
+
+	taken := jsonNames(tFile, msg, nil)
+	for _, f := range tMsg.Fields {
+		if f.IsDiscriminator {
+			taken = append(taken, f.JSONName)
+		}
+		for _, alias := range f.Aliases {
+			taken = append(taken, alias.JSONName)
+		}
+	}
+	for _, t := range taken {
+		if t == name {
+			warnf(tFile.Proto, declPath, "Discriminator %s for %s.%s collides with another field, ignoring it", name, msg.GetName(), decl.GetName())
+			return nil
+		}
+	}
+
+	f := &Field{
+		Name:            goCase(name),
+		JSONName:        name,
+		GoType:          "string",
+		IsPrimitive:     true,
+		IsDiscriminator: true,
+		Comment:         "Names the set field of ['" + strings.Join(members, "', '") + "'].",
+	}
+	f.Validation.EnumValues = members
+	tMsg.Fields = append(tMsg.Fields, f)
+
+	return f
+}
+
+// unionSchema returns the JSON Schema `oneOf` for a one-of group. Each member
+// is a branch, with an additional branch for when no member is set.
+// Discriminated unions identify the branch by the discriminator value instead
+// of the member being present.
+func unionSchema(group *OneOf) map[string]interface{} {
+	branches := []interface{}{}
+	if d := group.Discriminator; d != nil {
+		for _, f := range group.Fields {
+			branches = append(branches, map[string]interface{}{
+				"properties": map[string]interface{}{
+					d.JSONName: map[string]interface{}{"enum": []string{f.JSONName}},
+				},
+				"required": []string{d.JSONName},
+			})
+		}
+		branches = append(branches, map[string]interface{}{
+			"not": map[string]interface{}{"required": []string{d.JSONName}},
+		})
+
+		return map[string]interface{}{
+			"oneOf":         branches,
+			"discriminator": map[string]interface{}{"propertyName": d.JSONName},
+		}
+	}
+
+	anyMember := []interface{}{}
+	for _, f := range group.Fields {
+		branches = append(branches, map[string]interface{}{"required": []string{f.JSONName}})
+		anyMember = append(anyMember, map[string]interface{}{"required": []string{f.JSONName}})
+	}
+	branches = append(branches, map[string]interface{}{
+		"not": map[string]interface{}{"anyOf": anyMember},
+	})
+
+	return map[string]interface{}{"oneOf": branches}
+}
+
+// newField makes a field description from a protobuf field.
+func newField(tFile *File, protoMessage *descriptorpb.DescriptorProto, fieldPath []int32, protoField *descriptorpb.FieldDescriptorProto) *Field {
+	name, jsName := fieldNames(protoField)
+
 	example := ""
 	if e := proto.GetExtension(protoField.GetOptions(), annotation.E_Example).(string); e != "" {
 		example = e
 	}
 
 	f := &Field{
+		Path:        fieldPath,
 		Name:        name,
 		ProtoGoName: casing.Camel(protoField.GetName()),
 		JSONName:    jsName,
@@ -254,7 +763,7 @@ func newField(tFile *File, protoMessage *descriptorpb.DescriptorProto, fieldPath
 		Example:     example,
 	}
 
-	f.GoType, f.ProtoGoType, f.IsPrimitive, f.Enum = getType(tFile, "", protoField)
+	f.GoType, f.ProtoGoType, f.IsPrimitive, f.Enum = getType(tFile, "", fieldPath, protoField)
 	f.IsMap = strings.HasPrefix(f.GoType, "map[")
 
 	if !f.IsMap && protoField.Label != nil && *protoField.Label == descriptor.FieldDescriptorProto_LABEL_REPEATED {
@@ -273,7 +782,98 @@ func newField(tFile *File, protoMessage *descriptorpb.DescriptorProto, fieldPath
 		f.OneOf = casing.Camel(protoMessage.OneofDecl[int(*protoField.OneofIndex)].GetName())
 	}
 
+	if proto.GetExtension(protoField.GetOptions(), annotation.E_Flatten).(bool) {
+		flatten(tFile, protoMessage, protoField, f)
+	}
+
+	if t := proto.GetExtension(protoField.GetOptions(), annotation.E_GoType).(string); t != "" {
+		customType(tFile, protoMessage, protoField, f, t)
+	} else if proto.GetExtension(protoField.GetOptions(), annotation.E_FromProto).(string) != "" ||
+		proto.GetExtension(protoField.GetOptions(), annotation.E_ToProto).(string) != "" {
+		warnf(tFile.Proto, fieldPath, "Converter functions require a custom Go type, ignoring from_proto and to_proto for %s.%s", protoMessage.GetName(), protoField.GetName())
+	}
+
+	goNames, jsNames := takenNames(tFile, protoMessage, protoField)
+	for _, alias := range proto.GetExtension(protoField.GetOptions(), annotation.E_JsonAlias).([]string) {
+		if f.IsEmbedded {
+			warnf(tFile.Proto, fieldPath, "Flattened fields cannot have JSON aliases, ignoring alias %s for %s.%s", alias, protoMessage.GetName(), protoField.GetName())
+			continue
+		}
+
+		name := goCase("deprecated_" + alias)
+		if goNames[name] || jsNames[alias] {
+			warnf(tFile.Proto, fieldPath, "JSON alias %s for %s.%s collides with another field, ignoring it", alias, protoMessage.GetName(), protoField.GetName())
+			continue
+		}
+		goNames[name] = true
+		jsNames[alias] = true
+
+		// Aliases are extra deprecated fields which accept input using the old
+		// name. Validation rules are kept, but they are never required.
+		a := *f
+		a.Name = name
+		a.JSONName = alias
+		a.Comment = "Deprecated: Use '" + f.JSONName + "' instead."
+		a.Validation.Deprecated = true
+		a.Validation.IsRequired = false
+		a.Extensions = nil
+		f.Aliases = append(f.Aliases, &a)
+	}
+
+	if v := proto.GetExtension(protoField.GetOptions(), annotation.E_Validator).(string); v != "" {
+		if f.IsEmbedded {
+			warnf(tFile.Proto, fieldPath, "Flattened fields cannot have validators, ignoring validator %s for %s.%s", v, protoMessage.GetName(), protoField.GetName())
+		} else {
+			f.Validator = qualify(tFile, v)
+		}
+	}
+
+	// Fields can always be serialized, overriding the file's default. This is
+	// set after aliases as those are input-only. Only plain scalars and enums
+	// qualify: messages, lists, maps and bytes would be written as `null`, and
+	// one-of members must stay absent so only the set member is serialized.
+	scalar := protoField.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE &&
+		protoField.GetType() != descriptorpb.FieldDescriptorProto_TYPE_GROUP &&
+		protoField.GetType() != descriptorpb.FieldDescriptorProto_TYPE_BYTES &&
+		!f.IsRepeated && !f.IsMap && !f.IsEmbedded && f.OneOf == ""
+	if scalar {
+		f.EmitZero = proto.GetExtension(tFile.Proto.GetOptions(), annotation.E_EmitZeroFields).(bool)
+	}
+	if proto.HasExtension(protoField.GetOptions(), annotation.E_EmitZero) {
+		if v := proto.GetExtension(protoField.GetOptions(), annotation.E_EmitZero).(bool); v && !scalar {
+			warnf(tFile.Proto, fieldPath, "Only scalar and enum fields outside of one-of groups can emit zero values, ignoring emit_zero for %s.%s", protoMessage.GetName(), protoField.GetName())
+		} else {
+			f.EmitZero = v
+		}
+	}
+
+	if scope := proto.GetExtension(protoField.GetOptions(), annotation.E_RequiredScope).(string); scope != "" {
+		if f.IsEmbedded {
+			warnf(tFile.Proto, fieldPath, "Flattened fields cannot require a scope, ignoring scope %s for %s.%s", scope, protoMessage.GetName(), protoField.GetName())
+		} else {
+			f.RequiredScope = scope
+			f.setExtension("x-required-scope", fmt.Sprintf("%q", scope), false)
+		}
+	}
+
+	if proto.GetExtension(protoField.GetOptions(), annotation.E_Sensitive).(bool) {
+		if f.IsEmbedded {
+			warnf(tFile.Proto, fieldPath, "Flattened fields cannot be sensitive, mark the fields within %s.%s instead", protoMessage.GetName(), protoField.GetName())
+		} else {
+			f.IsSensitive = true
+			f.setExtension("x-sensitive", "true", false)
+		}
+	}
+
+	// Custom converter functions may return errors, which need to be passed
+	// up through any message using this field.
+	f.HasErrors = f.FromProtoFunc != "" || f.ToProtoFunc != ""
+	if protoField.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE && canFail(tFile, protoField.GetTypeName(), map[string]bool{}) {
+		f.HasErrors = true
+	}
+
 	convertValidation(protoField, f)
+	passthrough(tFile, protoMessage, protoField, f)
 
 	return f
 }
@@ -335,53 +935,181 @@ func processFile(tFile *File) {
 			p += "_"
 		}
 		tMsg := Message{
-			Name:        goCase(prefix + " " + msg.GetName()),
+			Path:        path,
+			Name:        goCase(prefix+" "+msg.GetName()) + goCase(tFile.Audience),
 			ProtoGoName: p + casing.Camel(msg.GetName(), casing.Identity),
 			Fields:      []*Field{},
-			OneOfs:      map[string][]*Field{},
+			OneOfs:      map[string]*OneOf{},
 			Comment:     getComments(tFile.Proto, path),
+			Extensions:  map[string]map[string]string{},
 		}
 
+		// All one-of groups by name, including wrapped ones which are not part
+		// of the message's own groups.
+		oneOfs := map[string]*OneOf{}
+
 		for j, f := range msg.Field {
 			// Only expose public fields!
-			if proto.GetExtension(f.GetOptions(), annotation.E_Public).(bool) || os.Getenv("ALL_PUBLIC") != "" {
+			if isPublic(tFile, f) {
 				fieldPath := append(append([]int32{}, path...), 2, int32(j))
 				tField := newField(tFile, msg, fieldPath, f)
 
+				if tField.IsEmbedded {
+					// Aliases for flattened messages must be unique within the package.
+					tField.Name = casing.LowerCamel(tMsg.Name+" "+tField.ProtoGoName, casing.Identity)
+				}
+
+				// Members of wrapped one-of groups live in the group's own message
+				// rather than in this one.
+				target := &tMsg
+
 				if tField.OneOf != "" {
-					// One-of fields have some extra rules and require some additional
-					// packages.
-					tFile.Imports["net/http"] = true
-					tFile.Imports["reflect"] = true
-					tFile.Imports["strings"] = true
-					tFile.Imports["github.com/danielgtaylor/huma"] = true
-					tMsg.OneOfs[tField.OneOf] = append(tMsg.OneOfs[tField.OneOf], tField)
+					// One-of fields have some extra rules, see `resolveGenerated`.
+					if oneOfs[tField.OneOf] == nil {
+						decl := msg.OneofDecl[f.GetOneofIndex()]
+						style := proto.GetExtension(decl.GetOptions(), annotation.E_OneofStyle).(annotation.OneofStyle)
+						group := &OneOf{
+							Name:    tField.OneOf,
+							IsUnion: style == annotation.OneofStyle_UNION,
+						}
+						oneOfs[tField.OneOf] = group
+
+						if style == annotation.OneofStyle_WRAPPED {
+							group.Wrapper = wrapper(&tMsg, decl, group)
+						} else {
+							tMsg.OneOfs[tField.OneOf] = group
+						}
+					}
+					group := oneOfs[tField.OneOf]
+					group.Fields = append(group.Fields, tField)
+					if group.Wrapper != nil {
+						target = group.Wrapper
+					}
+				}
+
+				for path, exts := range tField.Extensions {
+					if target.Extensions[path] == nil {
+						target.Extensions[path] = map[string]string{}
+					}
+					for name, value := range exts {
+						target.Extensions[path][name] = value
+					}
+				}
+
+				if tField.Validator != "" {
+					// Validators are called by the resolver.
+					target.HasValidators = true
+				}
+
+				if len(tField.Aliases) > 0 {
+					// Aliases are merged into their canonical fields by the resolver.
+					target.HasAliases = true
+				}
+
+				if tField.RequiredScope != "" {
+					target.HasScopes = true
+				}
+
+				if tField.EmitZero && !tField.Validation.IsRequired {
+					target.OptionalInputs = append(target.OptionalInputs, tField.JSONName)
+				} else if tField.Validation.IsRequired && len(tField.Aliases) > 0 {
+					// Required fields may be set via an alias instead, so they are
+					// checked by the resolver after merging.
+					target.OptionalInputs = append(target.OptionalInputs, tField.JSONName)
+				}
+
+				if tField.HasErrors {
+					target.HasErrors = true
 				}
 
 				// Add the new field to the message type.
-				tMsg.Fields = append(tMsg.Fields, tField)
+				target.Fields = append(target.Fields, tField)
+			}
+		}
+
+		// Wrapped groups are held by a field of this message, which can fail
+		// to convert if any of the members can.
+		for _, f := range tMsg.Fields {
+			if f.IsWrapper && oneOfs[f.ProtoGoName].Wrapper.HasErrors {
+				f.HasErrors = true
+				tMsg.HasErrors = true
 			}
 		}
 
 		// All fields are loaded, document one-ofs so users know which fields
 		// are mutually exclusive since we handle this with custom Huma validation
-		// logic instead of JSON Schema.
-		for _, fields := range tMsg.OneOfs {
+		// logic. Groups are processed in declaration order so the generated
+		// schema is stable.
+		unions := []interface{}{}
+		for i, decl := range msg.OneofDecl {
+			group := oneOfs[casing.Camel(decl.GetName())]
+			if group == nil {
+				// No public fields in this group.
+				continue
+			}
+
 			names := []string{}
-			for _, f := range fields {
+			for _, f := range group.Fields {
 				names = append(names, f.JSONName)
 			}
-			for _, f := range fields {
+			for _, f := range group.Fields {
 				if f.Comment != "" {
 					f.Comment += " "
 				}
 				f.Comment += "Only one of ['" + strings.Join(names, "', '") + "'] may be set."
 			}
+			for _, f := range tMsg.Fields {
+				if f.IsWrapper && f.ProtoGoName == group.Name {
+					f.Comment = "Only one of ['" + strings.Join(names, "', '") + "'] may be set."
+				}
+			}
+
+			if d := proto.GetExtension(decl.GetOptions(), annotation.E_Discriminator).(string); d != "" {
+				group.Discriminator = discriminator(tFile, &tMsg, msg, append(append([]int32{}, path...), 8, int32(i)), decl, group, d, names)
+			}
+
+			if group.IsUnion {
+				unions = append(unions, unionSchema(group))
+			}
 		}
 
-		if !tFile.KnownMap[tMsg.Name] {
+		// Unions are documented on the message schema itself. Multiple groups
+		// must all match, so they are combined via `allOf`.
+		if len(unions) == 1 {
+			for k, v := range unions[0].(map[string]interface{}) {
+				tMsg.setExtension("", k, fmt.Sprintf("%#v", v))
+			}
+		} else if len(unions) > 1 {
+			tMsg.setExtension("", "allOf", fmt.Sprintf("%#v", unions))
+		}
+
+		if tFile.Audience != "" && tFile.KnownMap[tMsg.Name] {
+			// Audience models are suffixed, which may clash with an existing type,
+			// e.g. `Message` for `partner` vs. a message named `MessagePartner`.
+			errorf(tFile.Proto, path, "Model %s for audience %s has the same name as an existing type, rename the message or audience", tMsg.Name, tFile.Audience)
+		} else if tFile.KnownMap[tMsg.Name] {
+			// Nesting can also produce the same name, e.g. `Foo.Bar` vs. `FooBar`,
+			// as can a one-of wrapper declared earlier.
+			errorf(tFile.Proto, path, "Model %s has the same name as an existing type, rename the message", tMsg.Name)
+		} else {
 			tFile.KnownMap[tMsg.Name] = true
 			tFile.Messages = append(tFile.Messages, tMsg)
+
+			for i, decl := range msg.OneofDecl {
+				group := oneOfs[casing.Camel(decl.GetName())]
+				if group == nil || group.Wrapper == nil {
+					continue
+				}
+
+				// Wrappers are named after the group, which may clash with a
+				// nested message, e.g. `Delivery.Destination` vs. `destination`.
+				if tFile.KnownMap[group.Wrapper.Name] {
+					errorf(tFile.Proto, append(append([]int32{}, path...), 8, int32(i)), "Wrapper %s for one-of %s.%s has the same name as an existing type, rename the message or one-of", group.Wrapper.Name, msg.GetName(), decl.GetName())
+					continue
+				}
+				tFile.KnownMap[group.Wrapper.Name] = true
+				tFile.Messages = append(tFile.Messages, *group.Wrapper)
+			}
 		}
 	}
 
@@ -394,7 +1122,7 @@ func processFile(tFile *File) {
 				tFile.Enums = append(tFile.Enums, *entry.enum)
 			}
 		} else {
-			fmt.Fprintln(os.Stderr, "Error: Cannot find enum "+prefix+"."+*enum.Name)
+			errorf(tFile.Proto, path, "Cannot find enum %s.%s", prefix, enum.GetName())
 		}
 	}
 
@@ -410,7 +1138,7 @@ func buildRegistry(file *protogen.File, messages []*descriptorpb.DescriptorProto
 	}
 
 	onEnum := func(prefix string, path []int32, enum *descriptor.EnumDescriptorProto) {
-		tEnum := buildEnum(file.Proto, prefix, path[:len(path)-1], int(path[len(path)-1]), enum)
+		tEnum := buildEnum(file.Proto, prefix, path[:len(path)-1], int(path[len(path)-1]), enum, file.Generate)
 		registry[prefix+"."+*enum.Name] = registryEntry{
 			file:           file,
 			enum:           tEnum,
@@ -425,13 +1153,30 @@ func run(input []byte) []byte {
 	// Protoc passes pluginpb.CodeGeneratorRequest in via stdin
 	// marshalled with Protobuf.
 	var req pluginpb.CodeGeneratorRequest
-	proto.Unmarshal(input, &req)
+	if err := proto.Unmarshal(input, &req); err != nil {
+		return errorResponse(fmt.Errorf("cannot read request: %w", err))
+	}
 
-	// Initialise our plugin with default options.
-	opts := protogen.Options{}
+	// Initialise our plugin with default options. Parameters protogen doesn't
+	// handle itself are passed on to our own options, e.g.
+	// `--huma_opt=audience=partner`. Invalid parameters are reported back to
+	// protoc rather than generating anything.
+	options := newOptions()
+	opts := protogen.Options{ParamFunc: options.Set}
 	plugin, err := opts.New(&req)
 	if err != nil {
-		panic(err)
+		return errorResponse(err)
+	}
+
+	// Disable HTML escaping in user templates, we are generating Go code!
+	pongo2.SetAutoescape(false)
+
+	// Problems are collected while generating so they can all be reported at
+	// once, see `warnf` and `errorf`.
+	diagnostics = &Diagnostics{}
+
+	if options.DumpRequest {
+		ioutil.WriteFile("request.pb", input, os.ModePerm)
 	}
 
 	// Create a map of files we've been explicitly asked to generate for fast
@@ -460,34 +1205,66 @@ func run(input []byte) []byte {
 		tFile := File{
 			Proto:         file.Proto,
 			PackageName:   fmt.Sprintf("%s", file.GoPackageName),
-			Imports:       map[string]bool{string(file.GoImportPath): true},
-			ProtoGoImport: *file.Proto.Options.GoPackage,
+			Options:       options,
+			GoPackage:     options.GoPackage(file),
+			Imports:       map[string]string{string(file.GoImportPath): string(file.GoPackageName)},
+			ProtoGoImport: string(file.GoImportPath),
 			KnownMap:      map[string]bool{},
 			Messages:      []Message{},
 		}
 
+		if value := proto.GetExtension(file.Proto.GetOptions(), annotation.E_GoPackage).(string); value != "" {
+			if _, err := parseGoPackage(value); err != nil {
+				warnf(file.Proto, []int32{8, annotation.E_GoPackage.Field}, "%s, using %s", err, tFile.GoPackage.ImportPath)
+			}
+		}
+
+		if hook := proto.GetExtension(file.Proto.GetOptions(), annotation.E_JsonAliasHook).(string); hook != "" {
+			tFile.AliasHook = qualify(&tFile, hook)
+		}
+
 		// Add all the public types from the file. This is the second of two passes
-		// we do when processing a file.
+		// we do when processing a file. Each audience adds another set of models
+		// to the same file.
 		processFile(&tFile)
+		for _, audience := range options.Audiences {
+			tFile.Audience = audience
+			processFile(&tFile)
+		}
 
 		// Only output the file if it has actual public stuff in it.
 		if len(tFile.Messages) > 0 || len(tFile.Enums) > 0 {
-			// Modify original filename. Example:
-			// path/to/package/file.proto => path/to/packagehuma/file.huma.go
-			p := file.Desc.Path()
-			base := path.Base(p)
-			dir := path.Dir(p)
-			if dir != "" {
-				dir += "huma"
+			// Modify the protobuf-go output filename, which respects the `paths`
+			// and `module` parameters just like protoc-gen-go. The Huma package
+			// is placed relative to the protobuf-go package based on their import
+			// paths. Example:
+			// path/to/package/file => path/to/packagehuma/file.huma.go
+			rel, err := filepath.Rel(string(file.GoImportPath), tFile.GoPackage.ImportPath)
+			if err != nil {
+				errorf(file.Proto, nil, "Cannot place package %s relative to %s: %v", tFile.GoPackage.ImportPath, file.GoImportPath, err)
+				continue
 			}
-			filename := path.Join(dir, base[:len(base)-len(path.Ext(base))]+".huma.go")
-			file := plugin.NewGeneratedFile(filename, ".")
-			if err := humaTemplate.ExecuteWriter(pongo2.Context{"file": tFile}, file); err != nil {
-				panic(err)
+			dir := path.Join(path.Dir(file.GeneratedFilenamePrefix), filepath.ToSlash(rel))
+			if dir == ".." || strings.HasPrefix(dir, "../") {
+				errorf(file.Proto, nil, "Package %s would be written outside of the output directory, use paths=import or move the file into a directory", tFile.GoPackage.ImportPath)
+				continue
+			}
+			filename := path.Join(dir, path.Base(file.GeneratedFilenamePrefix)+".huma.go")
+			emitHuma(plugin, &tFile, filename)
+
+			if len(tFile.Messages) > 0 {
+				// Structured logging support lives in its own file due to its build
+				// constraint, e.g. path/to/packagehuma/file.huma.slog.go
+				emitSlog(plugin, &tFile, strings.TrimSuffix(filename, ".go")+".slog.go")
 			}
 		}
 	}
 
+	// Any errors fail the whole request, so protoc doesn't write partial output.
+	if len(diagnostics.Errors) > 0 {
+		plugin.Error(errors.New(strings.Join(diagnostics.Errors, "\n")))
+	}
+
 	// Generate a response from our plugin and marshall as protobuf
 	stdout := plugin.Response()
 	out, err := proto.Marshal(stdout)
@@ -497,17 +1274,19 @@ func run(input []byte) []byte {
 	return out
 }
 
-func main() {
-	// Disable HTML escaping, we are generating Go code!
-	pongo2.SetAutoescape(false)
+// errorResponse returns a marshalled response which reports an error to
+// protoc without generating any files.
+func errorResponse(err error) []byte {
+	out, _ := proto.Marshal(&pluginpb.CodeGeneratorResponse{
+		Error: proto.String(err.Error()),
+	})
+	return out
+}
 
+func main() {
 	// Protoc passes our input data via stdin.
 	input, _ := ioutil.ReadAll(os.Stdin)
 
-	if os.Getenv("DUMP_REQUEST") != "" {
-		ioutil.WriteFile("request.pb", input, os.ModePerm)
-	}
-
 	out := run(input)
 
 	// Write the response to stdout, to be picked up by protoc.
@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/istreamlabs/protoc-gen-huma/example/package1huma"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensitiveLogValue(t *testing.T) {
+	msg := &package1huma.Message{Name: "foo"}
+	msg.CreatedBy = "alice@example.com"
+	msg.Revision = 2
+
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewJSONHandler(buf, nil)).Info("request", "body", msg)
+	assert.Contains(t, buf.String(), `"name":"foo"`)
+	assert.Contains(t, buf.String(), `"created_by":"***","revision":2`)
+	assert.NotContains(t, buf.String(), "alice")
+}
+
+func TestSensitiveLogValueCollections(t *testing.T) {
+	history := &package1huma.History{
+		Revisions: []*package1huma.Metadata{{CreatedBy: "alice@example.com", Revision: 1}, nil},
+		ByUser:    map[string]*package1huma.Metadata{"bob": {CreatedBy: "bob@example.com", Revision: 2}},
+	}
+
+	// Models in slices and maps are logged via their own `LogValue`.
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewJSONHandler(buf, nil)).Info("request", "body", history)
+	assert.Contains(t, buf.String(), `"revisions":{"0":{"created_by":"***","revision":1`)
+	assert.Contains(t, buf.String(), `"by_user":{"bob":{"created_by":"***","revision":2`)
+	assert.NotContains(t, buf.String(), "alice")
+	assert.NotContains(t, buf.String(), "bob@")
+}
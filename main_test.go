@@ -2,26 +2,40 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Jeffail/gabs/v2"
 	"github.com/danielgtaylor/huma"
 	"github.com/danielgtaylor/huma/responses"
+	"github.com/istreamlabs/protoc-gen-huma/annotation"
 	"github.com/istreamlabs/protoc-gen-huma/example/package1"
 	"github.com/istreamlabs/protoc-gen-huma/example/package1huma"
 	"github.com/istreamlabs/protoc-gen-huma/example/package2"
+	"github.com/istreamlabs/protoc-gen-huma/example/package2huma"
+	"github.com/istreamlabs/protoc-gen-huma/example/settingsapi"
+	"github.com/istreamlabs/protoc-gen-huma/internal/exampleconv"
+	"github.com/istreamlabs/protoc-gen-huma/schemaext"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
 //go:generate protoc --proto_path annotation annotation/huma.proto --go_out=./annotation --go_opt=paths=source_relative
 //go:generate go install
-//go:generate sh -c "rm -rf example && mkdir -p example && DUMP_REQUEST=1 protoc --proto_path=./proto -I=. --go_out=example --go_opt=paths=source_relative --huma_out=example proto/package1/* proto/package2/*"
+//go:generate sh -c "rm -rf example && mkdir -p example && protoc --proto_path=./proto -I=. --go_out=example --go_opt=paths=source_relative --huma_out=example --huma_opt=paths=source_relative,dump_request=true,audience=partner,audience=admin,template=header=testdata/templates/header.tpl,template=message=testdata/templates/message.tpl,template=field_tags=testdata/templates/field_tags.tpl proto/package1/* proto/package2/* && cp testdata/package1huma/* example/package1huma/"
 
 func TestMain(m *testing.M) {
 	// Run the code generator to get proper coverage reporting. We don't care
@@ -33,6 +47,258 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func TestOptions(t *testing.T) {
+	o := newOptions()
+	assert.NoError(t, o.Set("all_public", "true"))
+	assert.NoError(t, o.Set("package_suffix", "api"))
+	assert.NoError(t, o.Set("audience", "partner"))
+	assert.NoError(t, o.Set("audience", "admin"))
+	assert.True(t, o.AllPublic)
+	assert.Equal(t, "api", o.PackageSuffix)
+	assert.Equal(t, []string{"partner", "admin"}, o.Audiences)
+
+	assert.Error(t, o.Set("all_public", "maybe"))
+	assert.Error(t, o.Set("package_suffix", "Not-Valid"))
+	assert.Error(t, o.Set("audience", ""))
+
+	// Unknown parameters are reported back to protoc.
+	input, _ := proto.Marshal(&pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String("audience=partner,bogus=1"),
+	})
+	var resp pluginpb.CodeGeneratorResponse
+	assert.NoError(t, proto.Unmarshal(run(input), &resp))
+	assert.Contains(t, resp.GetError(), `unknown parameter "bogus"`)
+	assert.Empty(t, resp.File)
+}
+
+// generate runs the plugin on the example protos with the given parameters
+// and returns the generated files by name.
+func generate(t *testing.T, parameter string) map[string]string {
+	input, err := ioutil.ReadFile("request.pb")
+	assert.NoError(t, err)
+
+	var req pluginpb.CodeGeneratorRequest
+	assert.NoError(t, proto.Unmarshal(input, &req))
+	req.Parameter = proto.String(parameter)
+	input, _ = proto.Marshal(&req)
+
+	var resp pluginpb.CodeGeneratorResponse
+	assert.NoError(t, proto.Unmarshal(run(input), &resp))
+	assert.Empty(t, resp.GetError())
+
+	files := map[string]string{}
+	for _, f := range resp.File {
+		files[f.GetName()] = f.GetContent()
+	}
+	return files
+}
+
+func TestOutputPaths(t *testing.T) {
+	files := generate(t, "paths=source_relative")
+	assert.Contains(t, files, "package1huma/example.huma.go")
+	assert.Contains(t, files, "package1huma/example.huma.slog.go")
+
+	files = generate(t, "paths=import")
+	assert.Contains(t, files, "github.com/istreamlabs/protoc-gen-huma/example/package1huma/example.huma.go")
+
+	files = generate(t, "module=github.com/istreamlabs/protoc-gen-huma")
+	assert.Contains(t, files, "example/package1huma/example.huma.go")
+
+	files = generate(t, "package_suffix=api")
+	assert.Contains(t, files, "github.com/istreamlabs/protoc-gen-huma/example/package1api/example.huma.go")
+}
+
+func TestImportMapping(t *testing.T) {
+	files := generate(t, "paths=source_relative,Mpackage2/example2.proto=example.com/api/v2;apiv2")
+
+	// Cross-package references use the mapped import path. Like protoc-gen-go,
+	// imports are named after the last element of the import path.
+	package1 := files["package1huma/example.huma.go"]
+	assert.Contains(t, package1, `v2huma "example.com/api/v2huma"`)
+	assert.Contains(t, package1, "*v2huma.Message")
+	assert.Contains(t, package1, "v2huma.Fruits")
+
+	// The Huma package is placed relative to the protobuf-go package based on
+	// their import paths.
+	package2 := files["v2huma/example2.huma.go"]
+	assert.Contains(t, package2, "package apiv2huma")
+	assert.Contains(t, package2, `v2 "example.com/api/v2"`)
+	assert.Contains(t, package2, "proto *v2.Message")
+}
+
+func TestGoPackage(t *testing.T) {
+	// Set via the file option.
+	files := generate(t, "paths=source_relative")
+	assert.Contains(t, files, "settingsapi/settings.huma.go")
+	assert.Contains(t, files["settingsapi/settings.huma.go"], "package settingsapi")
+
+	// Set via a parameter, which cross-package references also use.
+	files = generate(t, "go_package=package2/example2.proto=example.com/api/v2/huma;v2api")
+	assert.Contains(t, files, "example.com/api/v2/huma/example2.huma.go")
+	assert.Contains(t, files["example.com/api/v2/huma/example2.huma.go"], "package v2api")
+
+	// Huma itself keeps its package name when another package has the same one.
+	package1 := files["github.com/istreamlabs/protoc-gen-huma/example/package1huma/example.huma.go"]
+	assert.Contains(t, package1, `huma1 "example.com/api/v2/huma"`)
+	assert.Contains(t, package1, "*huma1.Message")
+	assert.Contains(t, package1, "ctx huma.Context")
+
+	o := newOptions()
+	assert.Error(t, o.Set("go_package", "example.com/foo"))
+	assert.Error(t, o.Set("go_package", "foo.proto=example.com/foo;not-valid"))
+}
+
+func TestTemplates(t *testing.T) {
+	// The example is generated with the templates in testdata/templates.
+	assert.Equal(t, "package1.Message", (&package1huma.Message{}).AuditType())
+	f, _ := reflect.TypeOf(package1huma.Metadata{}).FieldByName("CreatedBy")
+	assert.Equal(t, "created_by,redact", f.Tag.Get("audit"))
+
+	files := generate(t, "paths=source_relative,template=header=testdata/templates/header.tpl")
+	assert.Contains(t, files["package1huma/example.huma.go"], "// Audit support for the models of package1/example.proto")
+	assert.NotContains(t, files["package1huma/example.huma.slog.go"], "Audit support")
+
+	// Model types can be resolved to the names of the file's imports, and
+	// other packages are imported as needed.
+	tpl := filepath.Join(t.TempDir(), "message.tpl")
+	assert.NoError(t, ioutil.WriteFile(tpl, []byte(`{% for field in msg.Fields %}{% if field.Name == "CrossPackage" %}var _ {{ type(field.GoType) }}{% endif %}{% endfor %}
+var _ = {{ ident("example.com/audit", "Record") }}`), 0644))
+	files = generate(t, "paths=source_relative,Mpackage2/example2.proto=example.com/api/v2;apiv2,template=message="+tpl)
+	assert.Contains(t, files["package1huma/example.huma.go"], "var _ *v2huma.Message")
+	assert.Contains(t, files["package1huma/example.huma.go"], `audit "example.com/audit"`)
+	assert.Contains(t, files["package1huma/example.huma.go"], "var _ = audit.Record")
+
+	o := newOptions()
+	assert.Error(t, o.Set("template", "footer=testdata/templates/header.tpl"))
+	assert.Error(t, o.Set("template", "message=testdata/templates/missing.tpl"))
+	assert.Error(t, o.Set("template", "message"))
+}
+
+func TestDiagnostics(t *testing.T) {
+	// Warnings are located via the source code info and don't stop generation.
+	files := generate(t, "paths=source_relative")
+	assert.Contains(t, files, "package1huma/example.huma.go")
+	assert.Contains(t, diagnostics.Warnings, "package1/example.proto:158:5: warning: Flattening Collision.metadata results in duplicate JSON field revision, keeping it nested")
+
+	// Each audience processes the file again, but problems are reported once.
+	generate(t, "paths=source_relative,audience=partner,audience=admin")
+	count := 0
+	for _, w := range diagnostics.Warnings {
+		if strings.Contains(w, "Flattening Collision.metadata") {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	// Errors are reported to protoc instead of writing any files.
+	input, _ := ioutil.ReadFile("request.pb")
+	var req pluginpb.CodeGeneratorRequest
+	assert.NoError(t, proto.Unmarshal(input, &req))
+	req.Parameter = proto.String("paths=source_relative,go_package=package2/example2.proto=example.com/other;other")
+	input, _ = proto.Marshal(&req)
+
+	var resp pluginpb.CodeGeneratorResponse
+	assert.NoError(t, proto.Unmarshal(run(input), &resp))
+	assert.Contains(t, resp.GetError(), "package2/example2.proto:1:1: Package example.com/other would be written outside of the output directory")
+	assert.Empty(t, resp.File)
+
+	// Unreadable requests are reported too.
+	assert.NoError(t, proto.Unmarshal(run([]byte("bad")), &resp))
+	assert.Contains(t, resp.GetError(), "cannot read request")
+}
+
+func TestEmit(t *testing.T) {
+	diagnostics = &Diagnostics{}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{})
+	assert.NoError(t, err)
+
+	tFile := &File{
+		Proto:     &descriptorpb.FileDescriptorProto{Name: proto.String("test.proto")},
+		GoPackage: GoPackage{ImportPath: "example.com/test", Name: "test"},
+		Imports: map[string]string{
+			"example.com/foo/v2": "foo",
+			"strings":            "strings",
+		},
+		Messages: []Message{{Name: "Broken", Path: []int32{4, 0}}},
+	}
+
+	// Only used packages are imported, using the names protogen picks.
+	e := newEmitter(plugin, tFile, "test.go")
+	e.P("package test")
+	e.P("func   Foo() string {")
+	e.P("return ", fmtPackage.Ident("Sprint"), "(", e.typ("foo.Bar"), ")")
+	e.P("}")
+	e.check()
+	content, err := e.g.Content()
+	assert.NoError(t, err)
+	expected := "package test\n\nimport (\n\tv2 \"example.com/foo/v2\"\n\tfmt \"fmt\"\n)\n\nfunc Foo() string {\n\treturn fmt.Sprint(v2.Bar)\n}\n"
+	assert.Equal(t, expected, string(content))
+	assert.Empty(t, diagnostics.Errors)
+
+	// Unparsable output points to the offending model.
+	e = newEmitter(plugin, tFile, "broken.go")
+	e.P("package test")
+	e.P()
+	e.P("type Broken struct {}")
+	e.P()
+	e.P("func (m *Broken) Foo() {")
+	e.P("return (")
+	e.P("}")
+	e.check()
+	assert.Len(t, diagnostics.Errors, 1)
+	assert.Contains(t, diagnostics.Errors[0], "test.proto: Generated code for Broken does not parse: 7:1")
+}
+
+func TestQualify(t *testing.T) {
+	diagnostics = &Diagnostics{}
+	tFile := &File{
+		Proto:     &descriptorpb.FileDescriptorProto{Name: proto.String("test.proto")},
+		GoPackage: GoPackage{ImportPath: "example.com/test", Name: "test"},
+		Imports:   map[string]string{},
+	}
+
+	// Major version suffixes are not part of the package name, which can also
+	// be given explicitly.
+	assert.Equal(t, "yaml.Node", qualify(tFile, "gopkg.in/yaml.v3.Node"))
+	assert.Equal(t, "*decimal.Decimal", qualify(tFile, "*example.com/decimal/v2.Decimal"))
+	assert.Equal(t, "uuid.UUID", qualify(tFile, "example.com/go-uuid;uuid.UUID"))
+	assert.Equal(t, "Local", qualify(tFile, "Local"))
+	assert.Equal(t, map[string]string{
+		"gopkg.in/yaml.v3":       "yaml",
+		"example.com/decimal/v2": "decimal",
+		"example.com/go-uuid":    "uuid",
+	}, tFile.Imports)
+
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{})
+	assert.NoError(t, err)
+	e := newEmitter(plugin, tFile, "test.go")
+	e.P("package test")
+	e.P("var _ ", e.typ("yaml.Node"))
+	e.P("var _ ", e.typ("*decimal.Decimal"))
+	e.check()
+	content, err := e.g.Content()
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `yaml "gopkg.in/yaml.v3"`)
+	assert.Contains(t, string(content), `v2 "example.com/decimal/v2"`)
+	assert.Empty(t, diagnostics.Errors)
+}
+
+func TestValidationTags(t *testing.T) {
+	typ := reflect.TypeOf(package1huma.Message{})
+
+	f, _ := typ.FieldByName("Num32")
+	assert.Equal(t, "0", f.Tag.Get("exclusiveMinimum"))
+	assert.Equal(t, "100", f.Tag.Get("exclusiveMaximum"))
+	assert.Equal(t, "2", f.Tag.Get("multipleOf"))
+
+	f, _ = typ.FieldByName("Num64")
+	assert.Equal(t, "0", f.Tag.Get("minimum"))
+	assert.Equal(t, "100", f.Tag.Get("maximum"))
+
+	f, _ = typ.FieldByName("Name")
+	assert.Equal(t, "64", f.Tag.Get("maxLength"))
+}
+
 func TestExcludedEnum(t *testing.T) {
 	keys := []string{}
 	for k := range package1huma.GlobalValuesMap {
@@ -42,6 +308,794 @@ func TestExcludedEnum(t *testing.T) {
 	assert.NotContains(t, keys, "TWO")
 }
 
+func TestEnumLabels(t *testing.T) {
+	// Casing applies to every value, while an explicit label always wins.
+	assert.Equal(t, package1.Status_STATUS_ACTIVE, package1huma.StatusValuesMap["status-active"])
+	assert.Equal(t, package1.Status_STATUS_IN_REVIEW, package1huma.StatusValuesMap["pending"])
+	assert.Equal(t, package1huma.StatusStatusInReview, package1huma.StatusNamesMap[package1.Status_STATUS_IN_REVIEW])
+
+	msg := package1huma.Message{}
+	msg.FromProto(&package1.Message{Status: package1.Status_STATUS_IN_REVIEW})
+
+	d, err := json.Marshal(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"status": "pending"}`, string(d))
+
+	assert.Equal(t, package1.Status_STATUS_IN_REVIEW, msg.ToProto(nil).Status)
+}
+
+func TestStripEnumPrefix(t *testing.T) {
+	assert.Equal(t, package1huma.Color("RED"), package1huma.ColorRed)
+	assert.Equal(t, package1huma.Color("DARK_BLUE"), package1huma.ColorDarkBlue)
+	assert.Equal(t, package1.Color_COLOR_DARK_BLUE, package1huma.ColorValuesMap[package1huma.ColorDarkBlue])
+}
+
+func TestExcludeEnumZero(t *testing.T) {
+	// Enum-level option.
+	assert.NotContains(t, package1huma.ColorNamesMap, package1.Color_COLOR_UNSPECIFIED)
+
+	// File-level option.
+	assert.NotContains(t, package2huma.FruitsValuesMap, package2huma.Fruits("NONE"))
+
+	// Zero values become absent fields and vice versa.
+	msg := package1huma.Message{}
+	msg.FromProto(&package1.Message{Color: package1.Color_COLOR_UNSPECIFIED, Fruit: package2.Fruits_NONE})
+
+	d, err := json.Marshal(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(d))
+
+	p := msg.ToProto(nil)
+	assert.Equal(t, package1.Color_COLOR_UNSPECIFIED, p.Color)
+	assert.Equal(t, package2.Fruits_NONE, p.Fruit)
+}
+
+func TestEnumAlias(t *testing.T) {
+	// The first value is canonical unless explicitly overridden.
+	assert.Equal(t, package1huma.PriorityHigh, package1huma.PriorityNamesMap[package1.Priority_HIGH])
+	assert.Equal(t, package1huma.PriorityMedium, package1huma.PriorityNamesMap[package1.Priority_NORMAL])
+
+	// All aliases are accepted as input.
+	for _, label := range []string{"HIGH", "URGENT"} {
+		msg := package1huma.Message{}
+		err := json.Unmarshal([]byte(`{"priority": "`+label+`"}`), &msg)
+		assert.NoError(t, err)
+
+		p := msg.ToProto(nil)
+		assert.Equal(t, package1.Priority_HIGH, p.Priority)
+
+		another := package1huma.Message{}
+		another.FromProto(p)
+		assert.Equal(t, package1huma.PriorityHigh, another.Priority)
+	}
+}
+
+func TestNumericEnum(t *testing.T) {
+	msg := package1huma.Message{}
+	err := json.Unmarshal([]byte(`{"level": 2, "levels": [1, 3]}`), &msg)
+	assert.NoError(t, err)
+	assert.Equal(t, package1huma.LevelInfo, msg.Level)
+
+	p := msg.ToProto(nil)
+	assert.Equal(t, package1.Level_LEVEL_INFO, p.Level)
+	assert.Equal(t, []package1.Level{package1.Level_LEVEL_DEBUG, package1.Level_LEVEL_ERROR}, p.Levels)
+
+	another := package1huma.Message{}
+	another.FromProto(p)
+
+	d, err := json.Marshal(another)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"level": 2, "levels": [1, 3]}`, string(d))
+
+	app := huma.New("Test Router", "1.0.0")
+	app.OpenAPIHook(schemaext.Hook(package1huma.Message{}))
+	app.Resource("/").Put("put-message", "docs",
+		responses.NoContent(),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Message
+	}) {
+		ctx.WriteHeader(http.StatusNoContent)
+	})
+
+	// Only the protobuf numbers are allowed.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level": 5}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Names are documented via a schema extension.
+	props := app.OpenAPI().Search("components", "schemas", "Message", "properties")
+	assert.Equal(t, []string{"Debug", "Info", "Error"}, props.Search("level", "x-enum-varnames").Data())
+	assert.Equal(t, []string{"Debug", "Info", "Error"}, props.Search("levels", "items", "x-enum-varnames").Data())
+}
+
+func TestFlatten(t *testing.T) {
+	msg := package1huma.Message{}
+	err := json.Unmarshal([]byte(`{"created_by": "alice", "revision": 2}`), &msg)
+	assert.NoError(t, err)
+
+	p := msg.ToProto(nil)
+	assert.Equal(t, "alice", p.Metadata.CreatedBy)
+	assert.Equal(t, int32(2), p.Metadata.Revision)
+
+	another := package1huma.Message{}
+	another.FromProto(p)
+
+	d, err := json.Marshal(another)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"created_by": "alice", "revision": 2}`, string(d))
+
+	// Empty flattened fields don't create the nested message.
+	assert.Nil(t, (&package1huma.Message{}).ToProto(nil).Metadata)
+
+	// Duplicate JSON names prevent flattening, so the field stays nested.
+	d, err = json.Marshal(package1huma.Collision{Revision: "a", Metadata: &package1huma.Metadata{Revision: 2}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"revision": "a", "metadata": {"revision": 2}}`, string(d))
+
+	generate(t, "paths=source_relative")
+	assert.Contains(t, diagnostics.Warnings, "package1/example.proto:158:5: warning: Flattening Collision.metadata results in duplicate JSON field revision, keeping it nested")
+}
+
+func TestCustomType(t *testing.T) {
+	page := package1huma.Page{}
+	err := json.Unmarshal([]byte(`{"link": {"slug": "home", "timeouts": [1500000000], "title": "Home"}}`), &page)
+	assert.NoError(t, err)
+	assert.Equal(t, exampleconv.Slug("home"), page.Link.Slug)
+
+	p, err := page.ToProto(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "home", p.Link.Slug)
+	assert.Equal(t, []string{"1.5s"}, p.Link.Timeouts)
+	assert.Equal(t, "Home", p.Link.Title)
+
+	another := package1huma.Page{}
+	_, err = another.FromProto(p)
+	assert.NoError(t, err)
+	assert.Equal(t, page.Link, another.Link)
+
+	// Converter errors are passed up through nested messages.
+	_, err = (&package1huma.Page{}).FromProto(&package1.Page{
+		Links: []*package1.Link{{Slug: "Not A Slug"}},
+	})
+	assert.True(t, errors.Is(err, exampleconv.ErrInvalidSlug))
+	assert.Contains(t, err.Error(), "links: slug:")
+}
+
+func TestConverterWithoutType(t *testing.T) {
+	diagnostics = &Diagnostics{}
+	tFile := &File{
+		Proto:   &descriptorpb.FileDescriptorProto{Name: proto.String("page.proto"), SourceCodeInfo: &descriptorpb.SourceCodeInfo{}},
+		Options: newOptions(),
+		Imports: map[string]string{},
+	}
+
+	// Converters are only used with a custom Go type, so they can't fail
+	// without one.
+	opts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(opts, annotation.E_FromProto, "example.com/conv.SlugFromProto")
+	protoField := &descriptorpb.FieldDescriptorProto{
+		Name:    proto.String("slug"),
+		Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		Options: opts,
+	}
+	msg := &descriptorpb.DescriptorProto{Name: proto.String("Page"), Field: []*descriptorpb.FieldDescriptorProto{protoField}}
+
+	f := newField(tFile, msg, []int32{4, 0, 2, 0}, protoField)
+	assert.False(t, f.HasErrors)
+	assert.Empty(t, f.FromProtoFunc)
+	assert.False(t, hasConverter(protoField))
+	assert.Equal(t, []string{"page.proto: warning: Converter functions require a custom Go type, ignoring from_proto and to_proto for Page.slug"}, diagnostics.Warnings)
+
+	// Nor on fields which can't have a custom type.
+	proto.SetExtension(opts, annotation.E_GoType, "example.com/conv.Slug")
+	assert.True(t, hasConverter(protoField))
+	protoField.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	assert.False(t, hasConverter(protoField))
+}
+
+func TestStripEnumPrefixCollision(t *testing.T) {
+	opts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(opts, annotation.E_StripPrefix, true)
+
+	file := &descriptorpb.FileDescriptorProto{SourceCodeInfo: &descriptorpb.SourceCodeInfo{}}
+	enum := &descriptorpb.EnumDescriptorProto{
+		Name:    proto.String("Shape"),
+		Options: opts,
+		Value: []*descriptorpb.EnumValueDescriptorProto{
+			{Name: proto.String("SHAPE_CIRCLE"), Number: proto.Int32(0)},
+			{Name: proto.String("CIRCLE"), Number: proto.Int32(1)},
+		},
+	}
+
+	// Both values would become `CIRCLE`, so the original names are kept.
+	e := buildEnum(file, ".example", []int32{5}, 0, enum, true)
+	assert.Equal(t, "SHAPE_CIRCLE", e.Values[0].Label)
+	assert.Equal(t, "CIRCLE", e.Values[1].Label)
+}
+
+func TestEnumLabelCollision(t *testing.T) {
+	diagnostics = &Diagnostics{}
+	enumOpts := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOpts, annotation.E_Casing, annotation.Casing_CASING_LOWER)
+	valueOpts := &descriptorpb.EnumValueOptions{}
+	proto.SetExtension(valueOpts, annotation.E_Label, "circle")
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name: proto.String("shape.proto"),
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+			Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{5, 0, 2, 1}, Span: []int32{4, 2, 20}},
+			},
+		},
+	}
+	enum := &descriptorpb.EnumDescriptorProto{
+		Name:    proto.String("Shape"),
+		Options: enumOpts,
+		Value: []*descriptorpb.EnumValueDescriptorProto{
+			{Name: proto.String("CIRCLE"), Number: proto.Int32(0)},
+			{Name: proto.String("ROUND"), Number: proto.Int32(1), Options: valueOpts},
+		},
+	}
+
+	// Both labels are `circle`, which is reported at the second value.
+	buildEnum(file, ".example", []int32{5}, 0, enum, true)
+	assert.Equal(t, []string{"shape.proto:5:3: Enum Shape has duplicate value circle, change its label or casing"}, diagnostics.Errors)
+
+	// Dependencies which aren't being generated only get a warning.
+	diagnostics = &Diagnostics{}
+	buildEnum(file, ".example", []int32{5}, 0, enum, false)
+	assert.Empty(t, diagnostics.Errors)
+	assert.Equal(t, []string{"shape.proto:5:3: warning: Enum Shape has duplicate value circle, change its label or casing"}, diagnostics.Warnings)
+}
+
+func TestJSONAlias(t *testing.T) {
+	msg := package1huma.Message{}
+	err := json.Unmarshal([]byte(`{"title": "old"}`), &msg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"title"}, msg.ResolveJSONAliases())
+	assert.Equal(t, "old", msg.Name)
+	assert.Equal(t, "old", msg.ToProto(nil).Name)
+
+	// The canonical field takes precedence and aliases are never output.
+	msg = package1huma.Message{}
+	err = json.Unmarshal([]byte(`{"name": "new", "label": "old"}`), &msg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"label"}, msg.ResolveJSONAliases())
+	assert.Equal(t, "new", msg.Name)
+
+	d, err := json.Marshal(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "new"}`, string(d))
+
+	// The resolver merges aliases and reports them via the hook, without
+	// setting response headers the operation doesn't declare.
+	exampleconv.ReportedAliases = nil
+	app := huma.New("Test Router", "1.0.0")
+	app.Resource("/").Put("put-message", "docs",
+		responses.OK().Model(package1huma.Message{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Message
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"title": "old"}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name": "old"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Warning"))
+	assert.Equal(t, []string{"Message.title"}, exampleconv.ReportedAliases)
+
+	// Required fields may be set via an alias, which is checked after merging.
+	// Error responses don't declare any extra headers either.
+	op := app.Resource("/bookmark").Put("put-bookmark", "docs",
+		responses.OK().Model(package1huma.Bookmark{}),
+		responses.BadRequest(),
+	)
+	op.RequestSchema(schemaext.RequestSchema(package1huma.Bookmark{}))
+	op.Run(func(ctx huma.Context, input struct {
+		Body package1huma.Bookmark
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/bookmark", strings.NewReader(`{"target": {"value": "a"}}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"link": {"value": "a"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/bookmark", strings.NewReader(`{}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Field 'link' is required in 'Bookmark'")
+	assert.Contains(t, w.Body.String(), `"location":"body.link"`)
+
+	assert.Equal(t, []string{"Message.title", "Bookmark.target"}, exampleconv.ReportedAliases)
+
+	// Files without a hook merge aliases silently.
+	op = app.Resource("/settings").Put("put-settings", "docs",
+		responses.OK().Model(settingsapi.Settings{}),
+	)
+	op.RequestSchema(schemaext.RequestSchema(settingsapi.Settings{}))
+	op.Run(func(ctx huma.Context, input struct {
+		Body settingsapi.Settings
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/settings", strings.NewReader(`{"attempts": 3}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"enabled": false, "retries": 3}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Warning"))
+}
+
+func TestJSONAliasCollision(t *testing.T) {
+	diagnostics = &Diagnostics{}
+	tFile := &File{
+		Proto:   &descriptorpb.FileDescriptorProto{Name: proto.String("page.proto"), SourceCodeInfo: &descriptorpb.SourceCodeInfo{}},
+		Options: newOptions(),
+		Imports: map[string]string{},
+	}
+	tFile.Options.AllPublic = true
+
+	titleOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(titleOpts, annotation.E_JsonAlias, []string{"heading"})
+	nameOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(nameOpts, annotation.E_JsonAlias, []string{"slug", "heading", "label"})
+	msg := &descriptorpb.DescriptorProto{
+		Name: proto.String("Page"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{Name: proto.String("title"), JsonName: proto.String("title"), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: titleOpts},
+			{Name: proto.String("name"), JsonName: proto.String("name"), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: nameOpts},
+			{Name: proto.String("slug"), JsonName: proto.String("slug"), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+			{Name: proto.String("deprecated_label"), JsonName: proto.String("deprecatedLabel"), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+		},
+	}
+
+	// Existing fields and aliases of earlier fields win, by JSON or Go name.
+	f := newField(tFile, msg, []int32{4, 0, 2, 1}, msg.Field[1])
+	assert.Empty(t, f.Aliases)
+	assert.Equal(t, []string{
+		"page.proto: warning: JSON alias slug for Page.name collides with another field, ignoring it",
+		"page.proto: warning: JSON alias heading for Page.name collides with another field, ignoring it",
+		"page.proto: warning: JSON alias label for Page.name collides with another field, ignoring it",
+	}, diagnostics.Warnings)
+}
+
+func TestAudience(t *testing.T) {
+	p := &package1.Message{
+		Name: "foo",
+		Sub:  &package1.Sub{CamelCaseEnum: package1.Sub_FOO, InternalId: "sub-1"},
+		CrossPackage: &package2.Message{
+			Name:     "crosspkg",
+			Supplier: "acme",
+		},
+	}
+
+	public := package1huma.Message{}
+	public.FromProto(p)
+	d, err := json.Marshal(public)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "foo", "sub": {"camel_case_enum": "FOO"}, "cross_package": {"name": "crosspkg"}}`, string(d))
+
+	// Nested and cross-package messages use the same audience.
+	partner := package1huma.MessagePartner{}
+	partner.FromProto(p)
+	d, err = json.Marshal(partner)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "foo", "sub": {"camel_case_enum": "FOO"}, "cross_package": {"name": "crosspkg", "supplier": "acme"}}`, string(d))
+
+	admin := package1huma.MessageAdmin{}
+	admin.FromProto(p)
+	d, err = json.Marshal(admin)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "foo", "sub": {"camel_case_enum": "FOO", "internal_id": "sub-1"}, "cross_package": {"name": "crosspkg", "supplier": "acme"}}`, string(d))
+
+	assert.Equal(t, "sub-1", admin.ToProto(nil).Sub.InternalId)
+}
+
+func TestAudienceCollision(t *testing.T) {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("name"),
+		JsonName: proto.String("name"),
+		Number:   proto.Int32(1),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	input, _ := proto.Marshal(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"widget.proto"},
+		Parameter:      proto.String("all_public=true,audience=partner"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("widget.proto"),
+			Package: proto.String("example"),
+			Syntax:  proto.String("proto3"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/widget")},
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("Widget"), Field: []*descriptorpb.FieldDescriptorProto{field}},
+				{Name: proto.String("WidgetPartner"), Field: []*descriptorpb.FieldDescriptorProto{field}},
+			},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+				Location: []*descriptorpb.SourceCodeInfo_Location{
+					{Path: []int32{4, 0}, Span: []int32{2, 0, 4, 1}},
+				},
+			},
+		}},
+	})
+
+	// The partner model for `Widget` would redefine `WidgetPartner`.
+	var resp pluginpb.CodeGeneratorResponse
+	assert.NoError(t, proto.Unmarshal(run(input), &resp))
+	assert.Equal(t, "widget.proto:3:1: Model WidgetPartner for audience partner has the same name as an existing type, rename the message or audience", resp.GetError())
+	assert.Empty(t, resp.File)
+}
+
+func TestWrapperCollision(t *testing.T) {
+	groupOpts := &descriptorpb.OneofOptions{}
+	proto.SetExtension(groupOpts, annotation.E_OneofStyle, annotation.OneofStyle_WRAPPED)
+	input, _ := proto.Marshal(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"delivery.proto"},
+		Parameter:      proto.String("all_public=true"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("delivery.proto"),
+			Package: proto.String("example"),
+			Syntax:  proto.String("proto3"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/delivery")},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name:       proto.String("Delivery"),
+				NestedType: []*descriptorpb.DescriptorProto{{Name: proto.String("Destination")}},
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:       proto.String("email"),
+					JsonName:   proto.String("email"),
+					Number:     proto.Int32(1),
+					Type:       descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					OneofIndex: proto.Int32(0),
+				}},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("destination"), Options: groupOpts}},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+				Location: []*descriptorpb.SourceCodeInfo_Location{
+					{Path: []int32{4, 0, 8, 0}, Span: []int32{4, 4, 6, 5}},
+				},
+			},
+		}},
+	})
+
+	// The wrapper for `destination` would redefine `Delivery.Destination`.
+	var resp pluginpb.CodeGeneratorResponse
+	assert.NoError(t, proto.Unmarshal(run(input), &resp))
+	assert.Equal(t, "delivery.proto:5:5: Wrapper DeliveryDestination for one-of Delivery.destination has the same name as an existing type, rename the message or one-of", resp.GetError())
+	assert.Empty(t, resp.File)
+}
+
+func TestRedact(t *testing.T) {
+	msg := package1huma.Message{
+		ComplexArray: []*package1huma.Another{{Value: "a", Cost: 1}, nil},
+		KvComplex:    map[string]*package1huma.Another{"b": {Value: "b", Cost: 2}},
+		Another:      &package1huma.Another{Value: "c", Cost: 3},
+	}
+
+	allowed := msg
+	allowed.Redact([]string{"billing:read"})
+	assert.Equal(t, int64(1), allowed.ComplexArray[0].Cost)
+
+	msg.Redact(nil)
+	assert.Equal(t, "a", msg.ComplexArray[0].Value)
+	assert.Equal(t, int64(0), msg.ComplexArray[0].Cost)
+	assert.Equal(t, int64(0), msg.KvComplex["b"].Cost)
+	assert.Equal(t, int64(0), msg.Another.Cost)
+
+	// Redacted fields are documented in the schema.
+	app := huma.New("Test Router", "1.0.0")
+	app.OpenAPIHook(schemaext.Hook(package1huma.Another{}))
+	app.Resource("/").Get("get-another", "docs",
+		responses.OK().Model(package1huma.Another{}),
+	).Run(func(ctx huma.Context) {})
+
+	props := app.OpenAPI().Search("components", "schemas", "Another", "properties")
+	assert.Equal(t, "billing:read", props.Search("cost", "x-required-scope").Data())
+}
+
+func TestSensitive(t *testing.T) {
+	msg := &package1huma.Message{Name: "foo"}
+	msg.CreatedBy = "alice@example.com"
+	msg.Revision = 2
+
+	// Sensitive fields in nested messages are masked however they get logged.
+	for _, out := range []string{fmt.Sprint(msg), fmt.Sprintf("%+v", *msg), fmt.Sprintf("%#v", msg)} {
+		assert.Contains(t, out, "foo")
+		assert.Contains(t, out, "***")
+		assert.NotContains(t, out, "alice")
+	}
+	assert.Contains(t, fmt.Sprint(msg), "Metadata:{CreatedBy:*** Revision:2 User: Team:}")
+
+	// The gateway can mask the same fields using the schema.
+	app := huma.New("Test Router", "1.0.0")
+	app.OpenAPIHook(schemaext.Hook(package1huma.Metadata{}))
+	app.Resource("/").Get("get-metadata", "docs",
+		responses.OK().Model(package1huma.Metadata{}),
+	).Run(func(ctx huma.Context) {})
+
+	props := app.OpenAPI().Search("components", "schemas", "Metadata", "properties")
+	assert.Equal(t, true, props.Search("created_by", "x-sensitive").Data())
+}
+
+func TestPassthrough(t *testing.T) {
+	f, _ := reflect.TypeOf(package1huma.Another{}).FieldByName("Value")
+	assert.Equal(t, "value,omitempty", f.Tag.Get("yaml"))
+	assert.Equal(t, "value", f.Tag.Get("db"))
+
+	// Tags emitted by the plugin cannot be overridden.
+	f, _ = reflect.TypeOf(package1huma.SubAdmin{}).FieldByName("InternalID")
+	assert.Equal(t, "internal_id,omitempty", f.Tag.Get("json"))
+
+	app := huma.New("Test Router", "1.0.0")
+	app.OpenAPIHook(schemaext.Hook(package1huma.Another{}))
+	app.Resource("/").Get("get-another", "docs",
+		responses.OK().Model(package1huma.Another{}),
+	).Run(func(ctx huma.Context) {})
+
+	value := app.OpenAPI().Search("components", "schemas", "Another", "properties", "value")
+	assert.Equal(t, true, value.Search("x-internal").Data())
+	assert.Equal(t, "media", value.Search("x-owner", "team").Data())
+}
+
+func TestEmitZero(t *testing.T) {
+	d, err := json.Marshal(settingsapi.Settings{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled": false, "retries": 0}`, string(d))
+
+	settings := (&settingsapi.Settings{}).FromProto(&package2.Settings{Note: "note"})
+	d, err = json.Marshal(settings)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled": false, "retries": 0, "note": "note"}`, string(d))
+
+	app := huma.New("Test Router", "1.0.0")
+	app.Resource("/default").Put("put-default", "docs",
+		responses.OK().Model(settingsapi.Settings{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body settingsapi.Settings
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	op := app.Resource("/").Put("put-settings", "docs",
+		responses.OK().Model(settingsapi.Settings{}),
+	)
+	op.RequestSchema(schemaext.RequestSchema(settingsapi.Settings{}))
+	op.Run(func(ctx huma.Context, input struct {
+		Body settingsapi.Settings
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	// Huma requires all fields without `omitempty` by default.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/default", strings.NewReader(`{}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/", strings.NewReader(`{}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"enabled": false, "retries": 0}`, w.Body.String())
+
+	// Responses always include the fields, but requests don't need them.
+	doc, err := gabs.ParseJSON(app.OpenAPI().Bytes())
+	assert.NoError(t, err)
+	schemas := doc.Search("components", "schemas")
+	assert.ElementsMatch(t, []interface{}{"enabled", "retries"}, schemas.Search("Settings", "required").Data())
+	assert.Nil(t, schemas.Search("put-settings-request", "required").Data())
+}
+
+func TestEmitZeroNonScalar(t *testing.T) {
+	diagnostics = &Diagnostics{}
+	fileOpts := &descriptorpb.FileOptions{}
+	proto.SetExtension(fileOpts, annotation.E_EmitZeroFields, true)
+	tFile := &File{
+		Proto:   &descriptorpb.FileDescriptorProto{Name: proto.String("settings.proto"), Options: fileOpts, SourceCodeInfo: &descriptorpb.SourceCodeInfo{}},
+		Options: newOptions(),
+		Imports: map[string]string{},
+	}
+
+	opts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(opts, annotation.E_EmitZero, true)
+	tags := &descriptorpb.FieldDescriptorProto{
+		Name:    proto.String("tags"),
+		Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		Label:   descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+		Options: opts,
+	}
+	data := &descriptorpb.FieldDescriptorProto{
+		Name: proto.String("data"),
+		Type: descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum(),
+	}
+	retries := &descriptorpb.FieldDescriptorProto{
+		Name: proto.String("retries"),
+		Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+	}
+	msg := &descriptorpb.DescriptorProto{Name: proto.String("Settings"), Field: []*descriptorpb.FieldDescriptorProto{tags, data, retries}}
+
+	// Empty lists would be serialized as `null`, so the annotation is ignored.
+	f := newField(tFile, msg, []int32{4, 0, 2, 0}, tags)
+	assert.False(t, f.EmitZero)
+	assert.Equal(t, []string{"settings.proto: warning: Only scalar and enum fields outside of one-of groups can emit zero values, ignoring emit_zero for Settings.tags"}, diagnostics.Warnings)
+
+	// The file default only applies to scalars, without a warning.
+	assert.False(t, newField(tFile, msg, []int32{4, 0, 2, 1}, data).EmitZero)
+	assert.True(t, newField(tFile, msg, []int32{4, 0, 2, 2}, retries).EmitZero)
+	assert.Len(t, diagnostics.Warnings, 1)
+}
+
+func TestValidator(t *testing.T) {
+	app := huma.New("Test Router", "1.0.0")
+	app.Resource("/").Put("put-page", "docs",
+		responses.OK().Model(package1huma.Page{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Page
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"link": {"country": "US"}}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"link": {"country": "XX"}, "links": [{"country": "DE"}, {"country": "YY"}]}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), exampleconv.ErrUnknownCountry.Error())
+	assert.Contains(t, w.Body.String(), `"location":"body.link.country","value":"XX"`)
+	assert.Contains(t, w.Body.String(), `"location":"body.links[1].country","value":"YY"`)
+	assert.NotContains(t, w.Body.String(), `"DE"`)
+}
+
+func TestResolveCustom(t *testing.T) {
+	app := huma.New("Test Router", "1.0.0")
+	app.Resource("/").Put("put-message", "docs",
+		responses.OK().Model(package1huma.Message{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Message
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"another": {"value": "fine"}}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Generated one-of validation and hand-written validation both run.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"tag": "foo", "another": {"value": "forbidden"}}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Only one of")
+	assert.Contains(t, w.Body.String(), `"location":"body.another.value"`)
+}
+
+func TestResolveLocations(t *testing.T) {
+	app := huma.New("Test Router", "1.0.0")
+	app.Resource("/").Put("put-message", "docs",
+		responses.OK().Model(package1huma.Message{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Message
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	// Resolvers report locations relative to their model, which Huma prefixes
+	// with the path through arrays, maps and nested messages.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{
+		"tag": "foo",
+		"count": 1,
+		"complex_array": [{}, {}, {"value": "forbidden"}],
+		"kv_complex": {"key": {"value": "forbidden"}},
+		"user": "alice",
+		"team": "media"
+	}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"location":"body.tag"`)
+	assert.Contains(t, w.Body.String(), `"location":"body.complex_array[2].value"`)
+	assert.Contains(t, w.Body.String(), `"location":"body.kv_complex.key.value"`)
+
+	// Flattened messages are resolved by their parent, so their fields are
+	// reported where they appear in the JSON, exactly once.
+	assert.Equal(t, 1, strings.Count(w.Body.String(), "Only one of ['user', 'team'] allowed in 'Metadata'"))
+	assert.Contains(t, w.Body.String(), `"location":"body.user"`)
+}
+
+func TestOneOfUnion(t *testing.T) {
+	shape := (&package1huma.Shape{}).FromProto(&package1.Shape{
+		Geometry: &package1.Shape_Side{Side: 2},
+		Fill:     &package1.Shape_Color{Color: "red"},
+	})
+	d, err := json.Marshal(shape)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "side", "side": 2, "color": "red"}`, string(d))
+
+	// Zero values are kept via the discriminator.
+	p := (&package1huma.Shape{Type: "radius"}).ToProto(nil)
+	assert.IsType(t, &package1.Shape_Radius{}, p.Geometry)
+
+	app := huma.New("Test Router", "1.0.0")
+	app.OpenAPIHook(schemaext.Hook(package1huma.Shape{}))
+	app.Resource("/").Put("put-shape", "docs",
+		responses.OK().Model(package1huma.Shape{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Shape
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"type": "radius", "side": 1}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must name the set field 'side'")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"type": "side", "side": 1}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Each union group is documented, combined via `allOf`.
+	groups := app.OpenAPI().Search("components", "schemas", "Shape", "allOf").Children()
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "type", groups[0].Search("discriminator", "propertyName").Data())
+	assert.Len(t, groups[0].Search("oneOf").Children(), 3)
+	assert.Equal(t, []string{"side"}, groups[0].Search("oneOf", "1", "properties", "type", "enum").Data())
+	assert.Equal(t, []string{"pattern"}, groups[1].Search("oneOf", "1", "required").Data())
+}
+
+func TestOneOfWrapped(t *testing.T) {
+	delivery := (&package1huma.Delivery{}).FromProto(&package1.Delivery{
+		Destination: &package1.Delivery_Webhook{Webhook: "https://example.com/"},
+	})
+	d, err := json.Marshal(delivery)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"destination": {"webhook": "https://example.com/"}}`, string(d))
+
+	p := delivery.ToProto(nil)
+	assert.Equal(t, "https://example.com/", p.GetWebhook())
+
+	// No group set means no wrapper object.
+	d, err = json.Marshal((&package1huma.Delivery{}).FromProto(&package1.Delivery{}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(d))
+
+	app := huma.New("Test Router", "1.0.0")
+	app.Resource("/").Put("put-delivery", "docs",
+		responses.OK().Model(package1huma.Delivery{}),
+	).Run(func(ctx huma.Context, input struct {
+		Body package1huma.Delivery
+	}) {
+		ctx.WriteModel(http.StatusOK, input.Body)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"destination": {"email": "a@example.com", "webhook": "https://example.com/"}}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"location":"body.destination.email"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"destination": {"email": "a@example.com"}}`))
+	app.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestHumaRoundtrip(t *testing.T) {
 	// Example protobuf message we will use to test various features.
 	proto := &package1.Message{
@@ -81,6 +1135,7 @@ func TestHumaRoundtrip(t *testing.T) {
 		CrossPackage: &package2.Message{
 			Name: "crosspkg",
 		},
+		Status: package1.Status_STATUS_ACTIVE,
 	}
 
 	// Expected JSON representation of the above. We will use this to both check
@@ -117,7 +1172,8 @@ func TestHumaRoundtrip(t *testing.T) {
 		"mp2t": true,
 		"cross_package": {
 			"name": "crosspkg"
-		}
+		},
+		"status": "status-active"
 	}`
 
 	// Set up a Huma instance & register a route. No middleware so that we
@@ -4,17 +4,26 @@ import "google.golang.org/protobuf/types/descriptorpb"
 
 // EnumValue represents one protobuf enum value.
 type EnumValue struct {
+	// Path is the source code info path of the protobuf enum value, used to
+	// report its location in diagnostics.
+	Path []int32
+
 	// Name is the Huma name for the enum value.
 	Name string
 
 	// Comment for the enum value, if any.
 	Comment string
 
-	// Label is the protobuf label for the enum value.
+	// Label is the JSON representation of the enum value. It defaults to the
+	// protobuf label but may be transformed by casing or overridden.
 	Label string
 
 	// Value is the protobuf integer assigned to the enum value.
 	Value int32
+
+	// IsAlias is true if another value with the same number is used when
+	// converting from protobuf. Aliases are still accepted as input.
+	IsAlias bool
 }
 
 // Enum represents a protobuf enum.
@@ -28,12 +37,20 @@ type Enum struct {
 	// Values represents all the defined values for the enum.
 	Values []EnumValue
 
+	// IsNumeric is true if the enum is represented by its protobuf integer
+	// values rather than string labels.
+	IsNumeric bool
+
 	// Comment is the leading comment for the enum type, if any.
 	Comment string
 }
 
 // Field represents a protobuf field within a message.
 type Field struct {
+	// Path is the source code info path of the protobuf field, used to report
+	// its location in diagnostics.
+	Path []int32
+
 	// Name is the Huma name for the field.
 	Name string
 
@@ -60,6 +77,35 @@ type Field struct {
 	// IsRepeated is true if the field is an array type.
 	IsRepeated bool
 
+	// IsCustom is true if the field uses a custom Go type rather than the
+	// protobuf-generated one.
+	IsCustom bool
+
+	// FromProtoFunc is the function used to convert a custom type field from
+	// its protobuf value, if any.
+	FromProtoFunc string
+
+	// ToProtoFunc is the function used to convert a custom type field to its
+	// protobuf value, if any.
+	ToProtoFunc string
+
+	// HasErrors is true if converting the field to or from protobuf may fail,
+	// i.e. it has custom converter functions or is a message which does.
+	HasErrors bool
+
+	// IsDiscriminator is true if the field names the set member of a one-of
+	// group rather than representing a protobuf field.
+	IsDiscriminator bool
+
+	// IsWrapper is true if the field holds the members of a `WRAPPED` one-of
+	// group. Its type converts to and from the parent protobuf message.
+	IsWrapper bool
+
+	// IsEmbedded is true if the field is a flattened message, embedded in the
+	// parent struct so that its fields are hoisted into the parent. Its name is
+	// an unexported alias for the message type.
+	IsEmbedded bool
+
 	// OneOf is set to the one-of group name if the field is part of a one-of
 	// group, otherwise it is blank.
 	OneOf string
@@ -75,10 +121,79 @@ type Field struct {
 
 	// Example provides an example value for documentation.
 	Example string
+
+	// RequiredScope is the scope a caller needs to see this field, if any.
+	// Fields are zeroed by the generated `Redact` method otherwise.
+	RequiredScope string
+
+	// Validator is a function validating the field value, if any.
+	Validator string
+
+	// EmitZero is true if the field is always serialized, even when it has a
+	// zero value.
+	EmitZero bool
+
+	// IsSensitive is true if the field is masked when logging the model.
+	IsSensitive bool
+
+	// ExtraTags are additional raw struct tags for the field, each preceded by
+	// a space, e.g. ` yaml:"name"`.
+	ExtraTags string
+
+	// Aliases are deprecated fields which accept input using old JSON names
+	// for this field.
+	Aliases []*Field
+
+	// Extensions contains JSON Schema extensions for this field as a map of
+	// schema path to extension name to Go value expression.
+	Extensions map[string]map[string]string
+}
+
+// setExtension sets a JSON Schema extension for the field to a Go value
+// expression. If `elem` is true then the extension applies to the array items
+// or map values rather than the field itself.
+func (f *Field) setExtension(name, value string, elem bool) {
+	path := f.JSONName
+	if elem && f.IsRepeated {
+		path += ".items"
+	} else if elem && f.IsMap {
+		path += ".additionalProperties"
+	}
+
+	if f.Extensions == nil {
+		f.Extensions = map[string]map[string]string{}
+	}
+	if f.Extensions[path] == nil {
+		f.Extensions[path] = map[string]string{}
+	}
+	f.Extensions[path][name] = value
+}
+
+// OneOf represents a protobuf one-of group.
+type OneOf struct {
+	// Name is the protobuf-generated Go field name for the group.
+	Name string
+
+	// Fields are the members of the group.
+	Fields []*Field
+
+	// IsUnion is true if the group is documented as a JSON Schema `oneOf`.
+	IsUnion bool
+
+	// Wrapper is the message holding the members of a `WRAPPED` group, if
+	// any.
+	Wrapper *Message
+
+	// Discriminator is a field naming the set member, if any.
+	Discriminator *Field
 }
 
 // Message represents a protobuf message type whithin a file.
 type Message struct {
+	// Path is the source code info path of the protobuf message, used to report
+	// its location in diagnostics.
+	Path []int32
+
 	// Name is the Huma name for this message type.
 	Name string
 
@@ -88,11 +203,42 @@ type Message struct {
 	// Fields is a slice of field definitions in the message.
 	Fields []*Field
 
-	// OneOfs is a map of one-of names to fields.
-	OneOfs map[string][]*Field
+	// OneOfs is a map of one-of names to groups.
+	OneOfs map[string]*OneOf
 
 	// Comment is the leading comment for the message, if any.
 	Comment string
+
+	// OptionalInputs are the JSON names of fields which are always serialized
+	// but are optional on input.
+	OptionalInputs []string
+
+	// HasScopes is true if any field requires a scope.
+	HasScopes bool
+
+	// HasValidators is true if any field has a custom validator function.
+	HasValidators bool
+
+	// HasAliases is true if any field has deprecated JSON aliases.
+	HasAliases bool
+
+	// HasErrors is true if converting the message to or from protobuf may fail,
+	// in which case the generated methods return an error.
+	HasErrors bool
+
+	// Extensions contains JSON Schema extensions for this message and its
+	// fields as a map of schema path to extension name to Go value expression.
+	// See the `schemaext` package.
+	Extensions map[string]map[string]string
+}
+
+// setExtension sets a JSON Schema extension for the message to a Go value
+// expression. An empty path applies to the message's own schema.
+func (m *Message) setExtension(path, name, value string) {
+	if m.Extensions[path] == nil {
+		m.Extensions[path] = map[string]string{}
+	}
+	m.Extensions[path][name] = value
 }
 
 // File represents a protobuf file.
@@ -106,9 +252,25 @@ type File struct {
 	// ProtoGoImport is the import path to the protobuf-generated Go output.
 	ProtoGoImport string
 
-	// Imports is a list of Go imports for the file, based on which types and
-	// features are used.
-	Imports map[string]bool
+	// Options configure code generation for the file.
+	Options *Options
+
+	// GoPackage is the Go package the Huma code is generated into.
+	GoPackage GoPackage
+
+	// Audience is the audience the models are currently being generated for,
+	// or an empty string for the default public models. Audience models use
+	// the camel cased audience as a type name suffix.
+	Audience string
+
+	// Imports maps Go import paths to the package names used for them in the
+	// types of the models, e.g. `package2huma` in `*package2huma.Message`. The
+	// generated code imports those which are used.
+	Imports map[string]string
+
+	// AliasHook is the function called with the model name and alias for each
+	// deprecated JSON field alias used in a request, if any.
+	AliasHook string
 
 	// KnownMap is used to keep track of which enums and messages have been seen
 	// before so we don't get duplicate definitions.
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/flosch/pongo2"
+	"github.com/istreamlabs/protoc-gen-huma/annotation"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+)
+
+// suffixRegex matches valid Go package name suffixes.
+var suffixRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// packageNameRegex matches valid Go package names.
+var packageNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// templatePoints are the extension points which user templates can be added
+// to via the `template` parameter.
+var templatePoints = []string{"header", "message", "field_tags"}
+
+// GoPackage describes the Go package that Huma code is generated into.
+type GoPackage struct {
+	// ImportPath is the Go import path of the package.
+	ImportPath string
+
+	// Name is the Go package name.
+	Name string
+}
+
+// versionRegex matches the major version suffix of an import path, e.g. `/v2`
+// or the `.v3` used by gopkg.in.
+var versionRegex = regexp.MustCompile(`[/.]v[0-9]+$`)
+
+// defaultPackageName returns the conventional package name for an import
+// path, which is its last element without a major version suffix. For example,
+// both `gopkg.in/yaml.v3` and `example.com/yaml/v3` give `yaml`.
+func defaultPackageName(importPath string) string {
+	return path.Base(versionRegex.ReplaceAllString(importPath, ""))
+}
+
+// parseGoPackage parses a package in the same format as the `go_package`
+// option, e.g. `example.com/foo/barhuma;barhuma`. The name defaults to the
+// last element of the import path, see `defaultPackageName`.
+func parseGoPackage(value string) (GoPackage, error) {
+	pkg := GoPackage{ImportPath: value, Name: defaultPackageName(value)}
+	if i := strings.Index(value, ";"); i >= 0 {
+		pkg.ImportPath = value[:i]
+		pkg.Name = value[i+1:]
+	}
+
+	if pkg.ImportPath == "" || !packageNameRegex.MatchString(pkg.Name) {
+		return pkg, fmt.Errorf("invalid Go package %q, expected an import path and optional package name, e.g. example.com/foo/barhuma;barhuma", value)
+	}
+
+	return pkg, nil
+}
+
+// Options configure code generation. They are set via protoc parameters, e.g.
+// `--huma_opt=all_public=true,audience=partner`.
+type Options struct {
+	// AllPublic exposes all fields as if they had the `public` annotation.
+	AllPublic bool
+
+	// PackageSuffix is appended to the protobuf Go package name, import path
+	// and directory to get those of the generated Huma package.
+	PackageSuffix string
+
+	// Audiences get additional models with the fields exposed to them.
+	Audiences []string
+
+	// DumpRequest writes the raw request to `request.pb` for debugging.
+	DumpRequest bool
+
+	// GoPackages override the generated Huma package of protobuf files by
+	// their path, e.g. `package2/example2.proto`.
+	GoPackages map[string]GoPackage
+
+	// Templates are user templates by extension point, e.g. `message`. Their
+	// output is added to the generated code, see `emitter.custom`.
+	Templates map[string]*pongo2.Template
+}
+
+// newOptions returns the default options. The `ALL_PUBLIC` and `DUMP_REQUEST`
+// environment variables are still supported for backward compatibility.
+func newOptions() *Options {
+	return &Options{
+		AllPublic:     os.Getenv("ALL_PUBLIC") != "",
+		PackageSuffix: "huma",
+		DumpRequest:   os.Getenv("DUMP_REQUEST") != "",
+		GoPackages:    map[string]GoPackage{},
+		Templates:     map[string]*pongo2.Template{},
+	}
+}
+
+// Set parses a single `name=value` parameter. It is called by protogen for
+// each parameter it doesn't handle itself.
+func (o *Options) Set(name, value string) error {
+	switch name {
+	case "all_public":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for parameter all_public, expected a boolean", value)
+		}
+		o.AllPublic = b
+	case "package_suffix":
+		if !suffixRegex.MatchString(value) {
+			return fmt.Errorf("invalid value %q for parameter package_suffix, expected lowercase letters, digits or underscores", value)
+		}
+		o.PackageSuffix = value
+	case "audience":
+		if value == "" {
+			return fmt.Errorf("parameter audience requires a value")
+		}
+		o.Audiences = append(o.Audiences, value)
+	case "dump_request":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for parameter dump_request, expected a boolean", value)
+		}
+		o.DumpRequest = b
+	case "go_package":
+		// The value is itself a mapping, e.g. `foo/bar.proto=example.com/foo;foo`.
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("invalid value %q for parameter go_package, expected <file>=<import path>[;<package name>]", value)
+		}
+		pkg, err := parseGoPackage(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid value %q for parameter go_package: %w", value, err)
+		}
+		o.GoPackages[parts[0]] = pkg
+	case "template":
+		// The value names an extension point and a template file, e.g.
+		// `message=templates/audit.tpl`.
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || !isTemplatePoint(parts[0]) || parts[1] == "" {
+			return fmt.Errorf("invalid value %q for parameter template, expected <extension point>=<file> with one of the extension points %s", value, strings.Join(templatePoints, ", "))
+		}
+		tpl, err := pongo2.FromFile(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid value %q for parameter template: %w", value, err)
+		}
+		o.Templates[parts[0]] = tpl
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	return nil
+}
+
+// isTemplatePoint returns whether a name is a valid template extension point.
+func isTemplatePoint(name string) bool {
+	for _, point := range templatePoints {
+		if point == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GoPackage returns the Go package to generate Huma code for a protobuf file
+// into. Parameters take precedence over the `go_package` file option, and by
+// default the package suffix is added to the protobuf-go package.
+func (o *Options) GoPackage(file *protogen.File) GoPackage {
+	if pkg, ok := o.GoPackages[file.Desc.Path()]; ok {
+		return pkg
+	}
+
+	if value := proto.GetExtension(file.Proto.GetOptions(), annotation.E_GoPackage).(string); value != "" {
+		if pkg, err := parseGoPackage(value); err == nil {
+			return pkg
+		}
+		// Invalid options are reported when the file is generated.
+	}
+
+	return GoPackage{
+		ImportPath: string(file.GoImportPath) + o.PackageSuffix,
+		Name:       string(file.GoPackageName) + o.PackageSuffix,
+	}
+}
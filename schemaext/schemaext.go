@@ -0,0 +1,221 @@
+// Package schemaext adjusts the JSON Schema Huma generates for generated
+// models. Huma has no struct tags for arbitrary schema keys like
+// `x-enum-varnames`, so generated models describe them via the `Extender`
+// interface and this package merges them into the OpenAPI document. It also
+// builds request schemas for models with fields that are always serialized.
+package schemaext
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/danielgtaylor/huma/schema"
+)
+
+// Extender is implemented by generated models which have schema extensions.
+// It returns a map of property paths to extension names and values. The path
+// is the JSON property name, optionally followed by dot-separated schema
+// keywords like `items` for arrays. An empty path applies to the model's own
+// schema.
+type Extender interface {
+	SchemaExtensions() map[string]map[string]interface{}
+}
+
+// OptionalInput is implemented by generated models with fields which are
+// always serialized, but are optional on input. It returns the JSON property
+// names of those fields.
+type OptionalInput interface {
+	OptionalInputFields() []string
+}
+
+// RequestSchema returns a request body schema for a model for use with
+// `huma.Operation.RequestSchema`. Huma marks all fields without `omitempty`
+// as required, so fields which are always serialized are made optional again.
+// Like Huma, it panics if the schema cannot be generated. Example:
+//
+//	op := app.Resource("/settings").Put("put-settings", "docs", ...)
+//	op.RequestSchema(schemaext.RequestSchema(examplehuma.Settings{}))
+//	op.Run(...)
+func RequestSchema(model interface{}) *schema.Schema {
+	t := reflect.TypeOf(model)
+	s, err := schema.GenerateWithMode(t, schema.ModeWrite, nil)
+	if err != nil {
+		panic(fmt.Errorf("unable to generate JSON schema: %w", err))
+	}
+	makeOptional(s, t)
+	return s
+}
+
+// makeOptional recursively removes always serialized fields of type `t` from
+// the required properties of the JSON Schema `s`.
+func makeOptional(s *schema.Schema, t reflect.Type) {
+	t = deref(t)
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if s.Items != nil {
+			makeOptional(s.Items, t.Elem())
+		}
+		return
+	case reflect.Map:
+		if props, ok := s.AdditionalProperties.(*schema.Schema); ok {
+			makeOptional(props, t.Elem())
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			makeOptional(s, f.Type)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+
+		if prop := s.Properties[jsonName(f)]; prop != nil {
+			makeOptional(prop, f.Type)
+		}
+	}
+
+	if o, ok := reflect.New(t).Interface().(OptionalInput); ok {
+		optional := map[string]bool{}
+		for _, name := range o.OptionalInputFields() {
+			optional[name] = true
+		}
+
+		required := []string{}
+		for _, name := range s.Required {
+			if !optional[name] {
+				required = append(required, name)
+			}
+		}
+		s.Required = nil
+		if len(required) > 0 {
+			s.Required = required
+		}
+	}
+}
+
+// jsonName returns the JSON property name of a struct field.
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name
+}
+
+// Hook returns a function for `huma.Router.OpenAPIHook` which adds schema
+// extensions for the given models, and any models nested within them, to the
+// component schemas of the generated OpenAPI document. Example:
+//
+//	app.OpenAPIHook(schemaext.Hook(examplehuma.Message{}))
+func Hook(models ...interface{}) func(*gabs.Container) {
+	return func(doc *gabs.Container) {
+		// Components are set as a Go struct, so round-trip through JSON to be
+		// able to walk and modify them.
+		components, err := gabs.ParseJSON(doc.Search("components").Bytes())
+		if err != nil {
+			return
+		}
+
+		for _, model := range models {
+			t := deref(reflect.TypeOf(model))
+			for name, s := range components.Search("schemas").ChildrenMap() {
+				if isSchemaFor(name, t.Name()) {
+					Apply(s, t)
+				}
+			}
+		}
+
+		doc.Set(components.Data(), "components")
+	}
+}
+
+// isSchemaFor returns whether a component schema name was generated from a
+// type name. Huma adds numeric suffixes to deduplicate names.
+func isSchemaFor(name, typeName string) bool {
+	if !strings.HasPrefix(name, typeName) {
+		return false
+	}
+	for _, r := range name[len(typeName):] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// deref returns the type pointed to by pointer types.
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// Apply recursively adds the schema extensions of type `t` to the JSON Schema
+// `s`, which must have been generated from that type.
+func Apply(s *gabs.Container, t reflect.Type) {
+	t = deref(t)
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items := s.Search("items"); items != nil {
+			Apply(items, t.Elem())
+		}
+		return
+	case reflect.Map:
+		if props := s.Search("additionalProperties"); props != nil {
+			Apply(props, t.Elem())
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	// Nested models first, so extensions set on a property of the outer model
+	// take precedence.
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			// Embedded struct fields are hoisted into this schema.
+			Apply(s, f.Type)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+
+		if prop := s.Search("properties", jsonName(f)); prop != nil {
+			Apply(prop, f.Type)
+		}
+	}
+
+	if e, ok := reflect.New(t).Interface().(Extender); ok {
+		for path, exts := range e.SchemaExtensions() {
+			target := s
+			if path != "" {
+				target = s.Search(append([]string{"properties"}, strings.Split(path, ".")...)...)
+			}
+			if target == nil || target.Data() == nil {
+				continue
+			}
+			for k, v := range exts {
+				target.Set(v, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package package1huma
+
+// Hand-written code which lives next to the generated models. This is copied
+// into the example output by `go generate`.
+
+import (
+	"net/http"
+
+	"github.com/danielgtaylor/huma"
+)
+
+// ResolveCustom adds hand-written validation to the generated resolver.
+func (m *Another) ResolveCustom(ctx huma.Context, r *http.Request) {
+	if m.Value == "forbidden" {
+		ctx.AddError(&huma.ErrorDetail{
+			Message:  "Value is not allowed",
+			Location: "value",
+			Value:    m.Value,
+		})
+	}
+}
@@ -0,0 +1 @@
+audit:"{{ field.JSONName }}{% if field.IsSensitive %},redact{% endif %}"
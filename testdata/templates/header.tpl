@@ -0,0 +1,2 @@
+// Audit support for the models of {{ file.Proto.GetName() }} is added by the
+// templates in testdata/templates.
@@ -0,0 +1,4 @@
+// AuditType returns the protobuf type of the model for audit logs.
+func (m *{{ msg.Name }}) AuditType() string {
+	return "{{ file.Proto.GetPackage() }}.{{ msg.ProtoGoName }}"
+}
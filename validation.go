@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/envoyproxy/protoc-gen-validate/validate"
@@ -16,8 +18,8 @@ type Validation struct {
 	Deprecated bool
 	IsRequired bool
 
-	// The template system can't determine the difference between nil and 0, so
-	// we use a boolean to determine if the field was set below.
+	// Zero is a valid bound, so we use a boolean to determine if the field was
+	// set below.
 	Minimum             float64
 	HasMinimum          bool
 	ExclusiveMinimum    float64
@@ -30,7 +32,7 @@ type Validation struct {
 	// Note: min/max length and items don't make sense when set to 0, so no need
 	// for the `HasXXX` booleans like above.
 	MinLength  int64
-	MaxLengh   int64
+	MaxLength  int64
 	Pattern    string
 	Format     string
 	MinItems   int64
@@ -40,6 +42,30 @@ type Validation struct {
 	MultipleOf int32
 }
 
+// setEnumValues sets the allowed values of an enum field. Numeric enums allow
+// the protobuf numbers and document the value names using `x-enum-varnames`.
+func setEnumValues(f *Field, values []EnumValue) {
+	f.Validation.EnumValues = []string{}
+
+	if !f.Enum.IsNumeric {
+		for _, v := range values {
+			f.Validation.EnumValues = append(f.Validation.EnumValues, v.Label)
+		}
+		return
+	}
+
+	names := []string{}
+	for _, v := range values {
+		if v.IsAlias {
+			// Same number as another value, so it can't be listed twice.
+			continue
+		}
+		f.Validation.EnumValues = append(f.Validation.EnumValues, strconv.Itoa(int(v.Value)))
+		names = append(names, v.Name)
+	}
+	f.setExtension("x-enum-varnames", fmt.Sprintf("%#v", names), true)
+}
+
 // convertValidation from protoc-gen-validate rules to Huma rules.
 func convertValidation(protoField *descriptorpb.FieldDescriptorProto, f *Field) {
 	if proto.GetExtension(protoField.GetOptions(), annotation.E_ReadOnly).(bool) {
@@ -55,11 +81,7 @@ func convertValidation(protoField *descriptorpb.FieldDescriptorProto, f *Field)
 	// subsequent validation rules can disable certain enum values for *just*
 	// this particular field.
 	if f.Enum != nil {
-		values := []string{}
-		for _, v := range f.Enum.Values {
-			values = append(values, v.Label)
-		}
-		f.Validation.EnumValues = values
+		setEnumValues(f, f.Enum.Values)
 	}
 
 	// protoc-gen-validate doesn't support multiple-of but JSON Schema & Huma do,
@@ -197,7 +219,7 @@ func convertValidation(protoField *descriptorpb.FieldDescriptorProto, f *Field)
 				f.Validation.MinLength = int64(*s.MinLen)
 			}
 			if s.MaxLen != nil {
-				f.Validation.MaxLengh = int64(*s.MaxLen)
+				f.Validation.MaxLength = int64(*s.MaxLen)
 			}
 			if s.Pattern != nil {
 				f.Validation.Pattern = *s.Pattern
@@ -227,7 +249,7 @@ func convertValidation(protoField *descriptorpb.FieldDescriptorProto, f *Field)
 
 		// Enum rules, e.g. filtering allowed values.
 		if f.Enum != nil {
-			values := []string{}
+			values := []EnumValue{}
 			notIn := []int32{}
 			if e := rules.GetEnum(); e != nil {
 				if e.NotIn != nil {
@@ -242,9 +264,9 @@ func convertValidation(protoField *descriptorpb.FieldDescriptorProto, f *Field)
 						continue outer
 					}
 				}
-				values = append(values, v.Label)
+				values = append(values, v)
 			}
-			f.Validation.EnumValues = values
+			setEnumValues(f, values)
 		}
 	}
 }